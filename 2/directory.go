@@ -0,0 +1,181 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"maps"
+	"strings"
+)
+
+// Nazwa kolumny tabeli PracownicyFTS, która zawiera tematy wszystkich
+// wyrazów z wiersza tabeli Pracownicy
+const Dane ColumnName = "dane"
+
+// Człon pytania: tematy wyrazów, z których co najmniej jeden musi
+// występować w wierszu wyniku lub, jeśli `Not` ma wartość `true`,
+// z których żaden nie może występować w wierszu wyniku
+type Term struct {
+	Stems []ASCIIStem
+	// Kolumna, w której są wyszukiwane tematy `Stems`: Tytuły,
+	// Imiona lub Nazwisko. Pusty łańcuch oznacza wszystkie kolumny
+	Column ColumnName
+	Not    bool
+}
+
+// Katalog pracowników AGH, w którym można wyszukiwać osoby
+type Directory interface {
+	// Index zapisuje w katalogu wiersze `rows`, które zawierają pola
+	// o nazwach wymienionych w zmiennej globalnej `ColumnNames`
+	Index(rows []map[ColumnName]string)
+	// Search zwraca te wiersze katalogu, które pasują do członów
+	// `terms`. Wynik ma taką postać, jak wynik funkcji ExecuteQuery
+	Search(terms []Term, cols []ColumnName) [][]string
+	// Vocabulary zwraca mapę tematów wyrazów występujących
+	// w katalogu na zbiory tych kolumn, w których występują te
+	// wyrazy, tak jak funkcja GetColumnsOfStems
+	Vocabulary() map[ASCIIStem]map[ColumnName]bool
+	// CompletionWords zwraca posortowane nazwiska, numery budynków
+	// i skróty nazw wydziałów występujące w katalogu, tak jak
+	// zapisano je w wierszach katalogu
+	CompletionWords() []string
+	// Close zamyka katalog
+	Close()
+}
+
+// MatchString zwraca zapytanie FTS5 złożone z członów `terms`
+//
+// Przykład:
+//
+// MatchString([]Term{
+// {Stems: []ASCIIStem{"prof"}, Column: Tytuły},
+// {Stems: []ASCIIStem{"nowakow", "nowak"}},
+// {Stems: []ASCIIStem{"c-1"}, Not: true},
+// }) == `tytuly : ("prof") AND ("nowakow" OR "nowak") NOT ("c-1")`
+func MatchString(terms []Term) string {
+	parts := map[bool][]string{}
+	for _, t := range terms {
+		part := JoinQuotedStems(t.Stems, ` OR `)
+		if t.Column != "" {
+			part = string(FTSColumnName(t.Column)) + " : " + part
+		}
+		parts[t.Not] = append(parts[t.Not], part)
+	}
+	ret := strings.Join(parts[false], " AND ")
+	if len(parts[true]) > 0 {
+		ret += " NOT " + strings.Join(parts[true], " NOT ")
+	}
+	return ret
+}
+
+// ReadCSV zwraca wiersze pliku tekstowego o nazwie `csvFilename`.
+// Pierwszy wiersz tego pliku zawiera nazwy kolumn. Każdy zwracany
+// wiersz zawiera też pola, które ReadCSV wydziela z pola `osoba` za
+// pomocą funkcji ParseOsoba
+func ReadCSV(csvFilename string) []map[ColumnName]string {
+	file := OpenFile(csvFilename)
+	defer file.Close()
+
+	csvFile := csv.NewReader(file)
+	rows := []map[ColumnName]string{}
+	firstRecord := true
+	header := []ColumnName{}
+	for {
+		rec, err := ReadCsvRecord(csvFile)
+		if err == io.EOF {
+			break
+		}
+		if firstRecord {
+			for _, c := range rec {
+				header = append(header, ColumnName(c))
+			}
+			firstRecord = false
+			continue
+		}
+		row := map[ColumnName]string{}
+		for i, c := range header {
+			row[c] = rec[i]
+		}
+		maps.Copy(row, ParseOsoba(row[Osoba]).NameColumns())
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// FTSFields zwraca pola wiersza tabeli PracownicyFTS, który odpowiada
+// wierszowi `row` tabeli Pracownicy: pole `Dane` i pola o nazwach
+// wymienionych w zmiennej globalnej `NameColumnNames`
+func FTSFields(row map[ColumnName]string) map[ColumnName]string {
+	stems := []ASCIIStem{}
+	for _, c := range ColumnNames {
+		as := ToASCIIString(row[c])
+		// Nie usuwaj piętra I
+		ss := ASCIIStringToASCIIStemSlice(as, c != Piętro)
+		stems = append(stems, ss...)
+	}
+	if strings.HasPrefix(row[Jednostka], "Wydział ") {
+		stems = append(stems,
+			AbbreviateFacultyName(row[Jednostka], true))
+		stems = append(stems,
+			AbbreviateFacultyName(row[Jednostka], false))
+	}
+	ret := map[ColumnName]string{Dane: JoinASCIIStems(stems)}
+	for _, c := range NameColumnNames {
+		as := ToASCIIString(row[c])
+		ret[c] = JoinASCIIStems(ASCIIStringToASCIIStemSlice(as, true))
+	}
+	return ret
+}
+
+// AddVocabulary dodaje do mapy `m` tematy wyrazów z wiersza `row`
+// tabeli Pracownicy i z pola `dane` odpowiedniego wiersza tabeli
+// PracownicyFTS
+func AddVocabulary(m *map[ASCIIStem]map[ColumnName]bool,
+	row map[ColumnName]string, dane string) {
+	for _, c := range ColumnNames {
+		as := ToASCIIString(row[c])
+		// Nie usuwaj piętra I
+		stems := ASCIIStringToASCIIStemSlice(as, c != Piętro)
+		for _, stem := range stems {
+			AddStemColumn(m, stem, c)
+		}
+	}
+	for _, stem := range strings.Fields(dane) {
+		AddStemColumn(m, ASCIIStem(stem), Jednostka)
+	}
+}
+
+// AddCompletionWords dodaje do zbioru `m` nazwisko, numer budynku
+// i skróty nazwy wydziału z wiersza `row` tabeli Pracownicy
+func AddCompletionWords(m map[string]bool, row map[ColumnName]string) {
+	words := []string{row[Nazwisko], row[Budynek]}
+	if strings.HasPrefix(row[Jednostka], "Wydział ") {
+		words = append(words,
+			string(AbbreviateFacultyName(row[Jednostka], true)),
+			string(AbbreviateFacultyName(row[Jednostka], false)))
+	}
+	for _, w := range words {
+		if w != "" {
+			m[w] = true
+		}
+	}
+}
+
+// NewResult zwraca wynik zapytania, który składa się tylko
+// z nagłówków kolumn: "lp" i `cols`
+func NewResult(cols []ColumnName) [][]string {
+	ret := [][]string{[]string{"lp"}}
+	for _, c := range cols {
+		ret = append(ret, []string{string(c)})
+	}
+	return ret
+}
+
+// AppendResultRow dopisuje do wyniku zapytania `res` kolejny wiersz
+// złożony z pól `row`
+func AppendResultRow(res [][]string, row []string) {
+	res[0] = append(res[0], fmt.Sprintf("%d", len(res[0])))
+	for i, c := range row {
+		res[i+1] = append(res[i+1], c)
+	}
+}
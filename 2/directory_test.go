@@ -0,0 +1,183 @@
+package main
+
+import (
+	"database/sql"
+	"maps"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestMatchString(t *testing.T) {
+	terms := []Term{
+		{Stems: []ASCIIStem{"prof"}, Column: Tytuły},
+		{Stems: []ASCIIStem{"nowakow", "nowak"}},
+		{Stems: []ASCIIStem{"c-1"}, Not: true},
+	}
+	want := `tytuly : ("prof") AND ("nowakow" OR "nowak") NOT ("c-1")`
+	if got := MatchString(terms); got != want {
+		t.Errorf("MatchString(%#v) == %#v want %#v", terms, got, want)
+	}
+}
+
+var directoryRows = []map[ColumnName]string{
+	{
+		Osoba:      "prof. dr hab. inż. Jan Nowak",
+		Stanowisko: "profesor",
+		Jednostka:  "Wydział Informatyki, Katedra Informatyki",
+		Budynek:    "C-1",
+		Piętro:     "I",
+		Pokój:      "101",
+		Telefon:    "+48 12 617 00 01",
+		Adres:      "ul. Kawiory 21",
+	},
+	{
+		Osoba:      "dr inż. Anna Kot",
+		Stanowisko: "adiunkt",
+		Jednostka:  "Wydział Informatyki, Katedra Informatyki",
+		Budynek:    "C-1",
+		Piętro:     "II",
+		Pokój:      "202",
+		Telefon:    "+48 12 617 00 02",
+		Adres:      "ul. Kawiory 21",
+	},
+	{
+		Osoba:      "mgr Piotr Kowalski",
+		Stanowisko: "specjalista",
+		Jednostka:  "Pion Kanclerza, Dział Spraw Osobowych",
+		Budynek:    "A-0",
+		Piętro:     "III",
+		Pokój:      "303",
+		Telefon:    "+48 12 617 00 03",
+		Adres:      "ul. Nowaka 5",
+	},
+	{
+		Osoba:      "dr hab. Ewa Król-Nowak",
+		Stanowisko: "profesor uczelni",
+		Jednostka:  "Wydział Fizyki i Informatyki Stosowanej",
+		Budynek:    "D-10",
+		Piętro:     "IV",
+		Pokój:      "404",
+		Telefon:    "+48 12 617 00 04",
+		Adres:      "ul. Reymonta 19",
+	},
+}
+
+// testDirectory sprawdza, czy katalog `dir`, do którego dodano
+// wiersze `directoryRows`, poprawnie odpowiada na pytania
+func testDirectory(t *testing.T, dir Directory) {
+	rows := []map[ColumnName]string{}
+	for _, row := range directoryRows {
+		row = maps.Clone(row)
+		maps.Copy(row, ParseOsoba(row[Osoba]).NameColumns())
+		rows = append(rows, row)
+	}
+	dir.Index(rows)
+	// Podpowiedzi to nazwiska i budynki, a nie tematy wyrazów
+	want := []string{"A-0", "C-1", "D-10", "Kot", "Kowalski",
+		"Król-Nowak", "Nowak"}
+	if got := dir.CompletionWords(); !slices.Equal(got, want) {
+		t.Errorf("CompletionWords() == %#v want %#v", got, want)
+	}
+	c := NewCompleter(dir.CompletionWords())
+	if got, want := c.Complete("kro"), []string{"l-Nowak"}; !slices.Equal(got, want) {
+		t.Errorf("Complete(%#v) == %#v want %#v", "kro", got, want)
+	}
+	colsOfStems := dir.Vocabulary()
+	data := []struct {
+		question string
+		want     []string
+	}{
+		{"kto ma na nazwisko Nowak?", []string{
+			"prof. dr hab. inż. Jan Nowak", "dr hab. Ewa Król-Nowak"}},
+		{"Nowak", []string{
+			"prof. dr hab. inż. Jan Nowak", "mgr Piotr Kowalski",
+			"dr hab. Ewa Król-Nowak"}},
+		{"jacy profesorowie pracują w budynku C-1?", []string{
+			"prof. dr hab. inż. Jan Nowak"}},
+		{"magistrowie", []string{"mgr Piotr Kowalski"}},
+		{"kto z budynku C-1 nie jest habilitowany?", []string{
+			"dr inż. Anna Kot"}},
+		{"kto ma telefon 12 617 00 03?", []string{"mgr Piotr Kowalski"}},
+		{"o imieniu Anna", []string{"dr inż. Anna Kot"}},
+	}
+	for _, d := range data {
+		as := ToASCIIString(TransformPhoneNumbers(d.question))
+		terms, cols, err := ParseQuestionTerms(as, colsOfStems)
+		if err != nil {
+			t.Errorf("ParseQuestionTerms(%#v) returned error %v",
+				d.question, err)
+			continue
+		}
+		res := dir.Search(terms, cols)
+		i := slices.Index(cols, Osoba)
+		if got := res[i+1][1:]; !slices.Equal(got, d.want) {
+			t.Errorf("Search(%#v) == %#v want %#v", terms, got, d.want)
+		}
+	}
+}
+
+func TestMemoryDirectory(t *testing.T) {
+	dir := NewMemoryDirectory()
+	defer dir.Close()
+	testDirectory(t, dir)
+}
+
+func TestSQLiteDirectory(t *testing.T) {
+	db, err := sql.Open(SQLiteDriver, ":memory:")
+	if err == nil {
+		_, err = db.Exec(`CREATE VIRTUAL TABLE t USING fts5(x)`)
+		db.Close()
+	}
+	if err != nil {
+		t.Skipf("SQLite3 with FTS5 is not available: %v", err)
+	}
+	dir := SQLiteDirectory{
+		CreateDatabase(filepath.Join(t.TempDir(), DbFilename))}
+	defer dir.Close()
+	testDirectory(t, dir)
+}
+
+// TestShippedDatabase sprawdza, czy baza danych w pliku `DbFilename`
+// ma kolumny, do których odwołują się zapytania o tytuły i nazwiska
+func TestShippedDatabase(t *testing.T) {
+	db, err := sql.Open(SQLiteDriver, ":memory:")
+	if err == nil {
+		_, err = db.Exec(`CREATE VIRTUAL TABLE t USING fts5(x)`)
+		db.Close()
+	}
+	if err != nil {
+		t.Skipf("SQLite3 with FTS5 is not available: %v", err)
+	}
+	dir := SQLiteDirectory{OpenDatabase(DbFilename)}
+	defer dir.Close()
+	c := NewCompleter(dir.CompletionWords())
+	if got := c.Complete("Kąk"); !slices.Contains(got, "ol") {
+		t.Errorf("Complete(%#v) == %#v want %#v among them",
+			"Kąk", got, "ol")
+	}
+	colsOfStems := dir.Vocabulary()
+	data := []struct {
+		question string
+		want     string
+	}{
+		{"jacy profesorowie pracują w budynku D-7?",
+			"prof. dr hab. inż. Zbigniew Kąkol"},
+		{"o nazwisku Kąkol", "prof. dr hab. inż. Zbigniew Kąkol"},
+	}
+	for _, d := range data {
+		as := ToASCIIString(TransformPhoneNumbers(d.question))
+		terms, cols, err := ParseQuestionTerms(as, colsOfStems)
+		if err != nil {
+			t.Errorf("ParseQuestionTerms(%#v) returned error %v",
+				d.question, err)
+			continue
+		}
+		res := dir.Search(terms, cols)
+		i := slices.Index(cols, Osoba)
+		if got := res[i+1][1:]; !slices.Contains(got, d.want) {
+			t.Errorf("Search(%#v) == %#v want %#v among them",
+				terms, got, d.want)
+		}
+	}
+}
@@ -5,16 +5,15 @@ import (
 	"encoding/csv"
 	"fmt"
 	"github.com/chzyer/readline"
-	_ "github.com/mattn/go-sqlite3"
 	"io"
 	"log"
 	"os"
 )
 
 // OpenDatabase otwiera bazę danych, która znajduje się w pliku
-// o nazwie `filename`
+// o nazwie `filename`, za pomocą sterownika `SQLiteDriver`
 func OpenDatabase(filename string) *sql.DB {
-	db, err := sql.Open("sqlite3", filename)
+	db, err := sql.Open(SQLiteDriver, filename)
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -121,9 +120,16 @@ func ReadCsvRecord(reader *csv.Reader) ([]string, error) {
 
 // CreateReadline tworzy nową instancję edytora wiersza poleceń. Każdy
 // wiersz wyświetlany przez tę instancję zaczyna się od łańcucha
-// `prompt`
-func CreateReadline(prompt string) *readline.Instance {
-	r, err := readline.New(prompt)
+// `prompt`. Ta instancja zapisuje historię poleceń w pliku o nazwie
+// `historyFile` i podpowiada dokończenia wyrazów za pomocą obiektu
+// `completer`
+func CreateReadline(prompt, historyFile string,
+	completer readline.AutoCompleter) *readline.Instance {
+	r, err := readline.NewEx(&readline.Config{
+		Prompt:       prompt,
+		HistoryFile:  historyFile,
+		AutoComplete: completer,
+	})
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"maps"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// MemoryDirectory implementuje katalog pracowników AGH w pamięci
+// operacyjnej za pomocą indeksu odwróconego. Dzieli pola tabeli
+// PracownicyFTS na tokeny tak samo, jak tokenizator unicode61 modułu
+// FTS5, więc odpowiada na pytania tak samo, jak SQLiteDirectory
+type MemoryDirectory struct {
+	// Wiersze tabeli Pracownicy
+	rows []map[ColumnName]string
+	// Tokeny pól odpowiednich wierszy tabeli PracownicyFTS
+	fields []map[ColumnName][]string
+	// Odwzorowuje każdy token na rosnący wycinek numerów tych
+	// wierszy, w których występuje ten token
+	index      map[string][]int
+	vocabulary map[ASCIIStem]map[ColumnName]bool
+	// Wyrazy, które podpowiada metoda CompletionWords
+	completions map[string]bool
+}
+
+// NewMemoryDirectory tworzy pusty katalog w pamięci operacyjnej
+func NewMemoryDirectory() *MemoryDirectory {
+	return &MemoryDirectory{
+		index:       map[string][]int{},
+		vocabulary:  map[ASCIIStem]map[ColumnName]bool{},
+		completions: map[string]bool{},
+	}
+}
+
+// tokenize dzieli łańcuch `s` na ciągi liter i cyfr
+func tokenize(s string) []string {
+	return strings.FieldsFunc(s, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
+
+// Index dodaje wiersze `rows` do katalogu
+func (d *MemoryDirectory) Index(rows []map[ColumnName]string) {
+	for _, row := range rows {
+		n := len(d.rows)
+		stored := maps.Clone(row)
+		stored[Telefon] = TransformPhoneNumbers(row[Telefon])
+		d.rows = append(d.rows, stored)
+		fields := map[ColumnName][]string{}
+		fts := FTSFields(row)
+		for c, s := range fts {
+			fields[c] = tokenize(s)
+			for _, tok := range fields[c] {
+				if postings := d.index[tok]; len(postings) == 0 ||
+					postings[len(postings)-1] != n {
+					d.index[tok] = append(postings, n)
+				}
+			}
+		}
+		d.fields = append(d.fields, fields)
+		AddVocabulary(&d.vocabulary, stored, fts[Dane])
+		AddCompletionWords(d.completions, stored)
+	}
+}
+
+// containsPhrase zwraca `true`, jeśli tokeny `phrase` występują
+// kolejno w wycinku `tokens`
+func containsPhrase(tokens, phrase []string) bool {
+	if len(phrase) == 0 {
+		return false
+	}
+	for i := 0; i+len(phrase) <= len(tokens); i++ {
+		if slices.Equal(tokens[i:i+len(phrase)], phrase) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesTerm zwraca `true`, jeśli w `n`-tym wierszu katalogu
+// występuje co najmniej jeden z tematów członu `t`. Wartość pola
+// `t.Not` nie ma znaczenia
+func (d *MemoryDirectory) matchesTerm(n int, t Term) bool {
+	for _, stem := range t.Stems {
+		phrase := tokenize(string(stem))
+		for c, tokens := range d.fields[n] {
+			if (t.Column == "" || t.Column == c) &&
+				containsPhrase(tokens, phrase) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// candidates zwraca rosnący wycinek numerów tych wierszy, w których
+// występuje pierwszy token co najmniej jednego z tematów członu `t`
+func (d *MemoryDirectory) candidates(t Term) []int {
+	ret := []int{}
+	for _, stem := range t.Stems {
+		if phrase := tokenize(string(stem)); len(phrase) > 0 {
+			ret = append(ret, d.index[phrase[0]]...)
+		}
+	}
+	slices.Sort(ret)
+	return slices.Compact(ret)
+}
+
+// Search zwraca te wiersze katalogu, w których występuje co najmniej
+// jeden temat każdego członu `terms` bez zaprzeczenia i nie występuje
+// żaden temat członów `terms` z zaprzeczeniem
+func (d *MemoryDirectory) Search(terms []Term, cols []ColumnName) [][]string {
+	ret := NewResult(cols)
+	i := slices.IndexFunc(terms, func(t Term) bool { return !t.Not })
+	if i < 0 {
+		return ret
+	}
+	for _, n := range d.candidates(terms[i]) {
+		if !slices.ContainsFunc(terms, func(t Term) bool {
+			return d.matchesTerm(n, t) == t.Not
+		}) {
+			row := []string{}
+			for _, c := range cols {
+				row = append(row, d.rows[n][c])
+			}
+			AppendResultRow(ret, row)
+		}
+	}
+	return ret
+}
+
+// Vocabulary zwraca mapę tematów wyrazów na zbiory tych kolumn,
+// w których występują te wyrazy
+func (d *MemoryDirectory) Vocabulary() map[ASCIIStem]map[ColumnName]bool {
+	return d.vocabulary
+}
+
+// CompletionWords zwraca posortowane nazwiska, numery budynków
+// i skróty nazw wydziałów występujące w katalogu
+func (d *MemoryDirectory) CompletionWords() []string {
+	return slices.Sorted(maps.Keys(d.completions))
+}
+
+// Close nic nie robi
+func (d *MemoryDirectory) Close() {
+}
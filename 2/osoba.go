@@ -0,0 +1,79 @@
+package main
+
+import (
+	"strings"
+)
+
+// Imię i nazwisko osoby oraz jej tytuły naukowe i zawodowe,
+// wydzielone z pola `osoba` tabeli Pracownicy
+type PersonName struct {
+	// Tytuły przed imieniem, na przykład ["dr", "hab.", "inż."]
+	Titles []string
+	// Imiona, na przykład ["Anna", "Maria"]
+	GivenNames []string
+	// Nazwisko, na przykład "Kot" lub "Achrem-Achremowicz"
+	Surname string
+	// Tytuły po przecinku, który następuje po nazwisku, na przykład
+	// "prof. AGH"
+	PostNominal string
+}
+
+// Tytuły naukowe i zawodowe, które nie kończą się kropką
+var AcademicTitles = map[string]bool{
+	"dr":  true,
+	"mgr": true,
+}
+
+// isAcademicTitle zwraca `true`, jeśli wyraz `w` jest tytułem albo
+// częścią tytułu naukowego lub zawodowego, na przykład "dr", "hab."
+// lub "inż."
+func isAcademicTitle(w string) bool {
+	return AcademicTitles[w] || strings.HasSuffix(w, ".")
+}
+
+// ParseOsoba dzieli zawartość pola `osoba` na tytuły, imiona,
+// nazwisko i tytuły po przecinku. Nazwisko to ostatni wyraz przed
+// przecinkiem
+//
+// Przykład:
+//
+// ParseOsoba("dr hab. inż. Anna Maria Kot, prof. AGH") == PersonName{
+// Titles:      []string{"dr", "hab.", "inż."},
+// GivenNames:  []string{"Anna", "Maria"},
+// Surname:     "Kot",
+// PostNominal: "prof. AGH",
+// }
+func ParseOsoba(osoba string) PersonName {
+	name, suffix, _ := strings.Cut(osoba, ",")
+	words := strings.Fields(name)
+	n := 0
+	for n < len(words)-1 && isAcademicTitle(words[n]) {
+		n++
+	}
+	ret := PersonName{
+		Titles:      words[:n],
+		GivenNames:  []string{},
+		PostNominal: strings.TrimSpace(suffix),
+	}
+	if n < len(words) {
+		ret.GivenNames = words[n : len(words)-1]
+		ret.Surname = words[len(words)-1]
+	}
+	return ret
+}
+
+// Name zwraca imiona i nazwisko osoby `p` rozdzielone spacjami
+func (p PersonName) Name() string {
+	return strings.TrimSpace(
+		strings.Join(p.GivenNames, " ") + " " + p.Surname)
+}
+
+// NameColumns zwraca wartości pól tytuły, imiona i nazwisko tabeli
+// Pracownicy dla osoby `p`
+func (p PersonName) NameColumns() map[ColumnName]string {
+	return map[ColumnName]string{
+		Tytuły:   strings.Join(p.Titles, " "),
+		Imiona:   strings.Join(p.GivenNames, " "),
+		Nazwisko: p.Surname,
+	}
+}
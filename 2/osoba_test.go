@@ -0,0 +1,66 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseOsoba(t *testing.T) {
+	data := []struct {
+		in   string
+		want PersonName
+	}{
+		{
+			"dr hab. inż. Anna Maria Kot, prof. AGH",
+			PersonName{
+				[]string{"dr", "hab.", "inż."},
+				[]string{"Anna", "Maria"}, "Kot", "prof. AGH",
+			},
+		},
+		{
+			"prof. zw. dr hab. Jan Nowak",
+			PersonName{
+				[]string{"prof.", "zw.", "dr", "hab."},
+				[]string{"Jan"}, "Nowak", "",
+			},
+		},
+		{
+			"mgr Dorota Achrem-Achremowicz",
+			PersonName{
+				[]string{"mgr"},
+				[]string{"Dorota"}, "Achrem-Achremowicz", "",
+			},
+		},
+		{
+			"Andrzej Adamczyk",
+			PersonName{
+				[]string{}, []string{"Andrzej"}, "Adamczyk", "",
+			},
+		},
+		{
+			"",
+			PersonName{[]string{}, []string{}, "", ""},
+		},
+	}
+	for _, d := range data {
+		if got := ParseOsoba(d.in); !reflect.DeepEqual(got, d.want) {
+			t.Errorf("ParseOsoba(%#v) == %#v want %#v", d.in, got, d.want)
+		}
+	}
+}
+
+func TestNameColumns(t *testing.T) {
+	in := "lek. med. Ewa Lis"
+	want := map[ColumnName]string{
+		Tytuły:   "lek. med.",
+		Imiona:   "Ewa",
+		Nazwisko: "Lis",
+	}
+	if got := ParseOsoba(in).NameColumns(); !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseOsoba(%#v).NameColumns() == %#v want %#v",
+			in, got, want)
+	}
+	if got := ParseOsoba(in).Name(); got != "Ewa Lis" {
+		t.Errorf(`ParseOsoba(%#v).Name() == %#v want "Ewa Lis"`, in, got)
+	}
+}
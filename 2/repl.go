@@ -0,0 +1,162 @@
+package main
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// Nazwa pliku w katalogu domowym użytkownika, w którym skos zapisuje
+// historię pytań między kolejnymi uruchomieniami
+const HistoryFilename = ".skos_history"
+
+// HistoryPath zwraca ścieżkę pliku z historią pytań. Jeśli nie da się
+// ustalić katalogu domowego użytkownika, plik z historią pytań leży
+// w bieżącym katalogu
+func HistoryPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return HistoryFilename
+	}
+	return filepath.Join(home, HistoryFilename)
+}
+
+// Format, w którym skos wypisuje wyniki zapytań
+type Format string
+
+const (
+	TableFormat Format = "table"
+	VCardFormat Format = "vcard"
+)
+
+// Funkcje, które wypisują wyniki zapytań w kolejnych formatach
+var Displays = map[Format]func([][]string){
+	TableFormat: DisplayResult,
+	VCardFormat: DisplayVCards,
+}
+
+// Tekst, który wypisuje polecenie \help
+const HelpText = `Zadaj pytanie po polsku, np. "kto pracuje w budynku C-1?"
+Polecenia:
+  \help            wypisz tę pomoc
+  \format          wypisz bieżący format odpowiedzi
+  \format table    wypisuj odpowiedzi jako tabele
+  \format vcard    wypisuj odpowiedzi jako wizytówki vCard
+  \vcard pytanie   odpowiedz na pytanie wizytówkami vCard
+  \quit            zakończ program
+`
+
+// Polecenie użytkownika programu skos
+type Command struct {
+	// Pytanie, na które trzeba odpowiedzieć, lub pusty łańcuch
+	Question string
+	// Format odpowiedzi na pytanie `Question` albo nowy format
+	// odpowiedzi na kolejne pytania, jeśli `Question` jest puste
+	Format Format
+	// Tekst, który trzeba wypisać na standardowym wyjściu
+	Output string
+	// `true`, jeśli trzeba zakończyć program
+	Quit bool
+}
+
+// ParseCommand przetwarza wiersz `line` wprowadzony przez użytkownika
+// na polecenie. Wiersz, który nie zaczyna się ukośnikiem odwrotnym,
+// jest pytaniem, na które trzeba odpowiedzieć w bieżącym formacie
+// `format`
+func ParseCommand(line string, format Format) (Command, error) {
+	line = strings.TrimSpace(line)
+	if !strings.HasPrefix(line, `\`) {
+		return Command{Question: line, Format: format}, nil
+	}
+	name, arg, _ := strings.Cut(line, " ")
+	arg = strings.TrimSpace(arg)
+	switch name {
+	case `\help`:
+		return Command{Format: format, Output: HelpText}, nil
+	case `\quit`:
+		return Command{Format: format, Quit: true}, nil
+	case `\format`:
+		if arg == "" {
+			return Command{Format: format,
+				Output: fmt.Sprintf("Format odpowiedzi: %s\n", format)}, nil
+		}
+		if Displays[Format(arg)] == nil {
+			return Command{}, fmt.Errorf("Nie znam formatu %s", arg)
+		}
+		return Command{Format: Format(arg)}, nil
+	case `\vcard`:
+		if arg == "" {
+			return Command{}, errors.New("Po poleceniu \\vcard brak pytania")
+		}
+		return Command{Question: arg, Format: VCardFormat}, nil
+	}
+	return Command{}, fmt.Errorf("Nie znam polecenia %s. Wpisz \\help", name)
+}
+
+// Completer podpowiada dokończenia wyrazów, które użytkownik wpisuje
+// w wierszu poleceń. Implementuje interfejs readline.AutoCompleter
+type Completer struct {
+	// Polecenia i wyrazy, które można podpowiadać, posortowane
+	// według kluczy `keys`
+	words []string
+	// keys[i] to wyraz words[i] zmieniony funkcją ToASCIIString
+	keys []string
+}
+
+// NewCompleter tworzy obiekt, który podpowiada polecenia programu
+// skos i wyrazy `words`, np. nazwiska, numery budynków i skróty nazw
+// wydziałów zwrócone przez metodę Directory.CompletionWords
+func NewCompleter(words []string) *Completer {
+	words = slices.Concat(
+		[]string{`\help`, `\format`, `\vcard`, `\quit`}, words)
+	slices.SortFunc(words, func(a, b string) int {
+		return cmp.Or(strings.Compare(string(ToASCIIString(a)),
+			string(ToASCIIString(b))), strings.Compare(a, b))
+	})
+	words = slices.Compact(words)
+	keys := []string{}
+	for _, w := range words {
+		keys = append(keys, string(ToASCIIString(w)))
+	}
+	return &Completer{words, keys}
+}
+
+// Complete zwraca dokończenia wyrazu `prefix`, posortowane według
+// wyrazów zmienionych funkcją ToASCIIString. Wielkość liter
+// i znaki diakrytyczne w `prefix` nie mają znaczenia, więc "kąk"
+// i "Kak" dokańcza się do "Kąkol"
+func (c *Completer) Complete(prefix string) []string {
+	p := string(ToASCIIString(prefix))
+	if p == "" {
+		return nil
+	}
+	// ToASCIIString nie zmienia liczby runów
+	n := utf8.RuneCountInString(prefix)
+	i, _ := slices.BinarySearch(c.keys, p)
+	ret := []string{}
+	for ; i < len(c.keys) && strings.HasPrefix(c.keys[i], p); i++ {
+		if r := []rune(c.words[i]); n <= len(r) {
+			ret = append(ret, string(r[n:]))
+		}
+	}
+	return ret
+}
+
+// Do podpowiada dokończenia tego wyrazu wiersza `line`, który kończy
+// się na pozycji `pos`
+func (c *Completer) Do(line []rune, pos int) ([][]rune, int) {
+	start := pos
+	for start > 0 && line[start-1] != ' ' {
+		start--
+	}
+	ret := [][]rune{}
+	for _, s := range c.Complete(string(line[start:pos])) {
+		ret = append(ret, []rune(s))
+	}
+	return ret, pos - start
+}
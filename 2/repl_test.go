@@ -0,0 +1,61 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseCommand(t *testing.T) {
+	data := []struct {
+		line    string
+		want    Command
+		wantErr bool
+	}{
+		{"kto to jest?", Command{Question: "kto to jest?", Format: TableFormat}, false},
+		{`\quit`, Command{Format: TableFormat, Quit: true}, false},
+		{`\help`, Command{Format: TableFormat, Output: HelpText}, false},
+		{`\format`, Command{Format: TableFormat, Output: "Format odpowiedzi: table\n"}, false},
+		{`\format vcard`, Command{Format: VCardFormat}, false},
+		{`\format html`, Command{}, true},
+		{`\vcard kto to jest?`, Command{Question: "kto to jest?", Format: VCardFormat}, false},
+		{`\vcard`, Command{}, true},
+		{`\wyjdz`, Command{}, true},
+	}
+	for _, d := range data {
+		got, err := ParseCommand(d.line, TableFormat)
+		if got != d.want || (err != nil) != d.wantErr {
+			t.Errorf("ParseCommand(%#v, TableFormat) == %#v, %v want %#v, error: %v",
+				d.line, got, err, d.want, d.wantErr)
+		}
+	}
+}
+
+func TestCompleter(t *testing.T) {
+	c := NewCompleter([]string{
+		"Nowak", "Nowakowska", "Łuczak", "Kąkol", "C-1", "C-2", "Nowak"})
+	data := []struct {
+		prefix string
+		want   []string
+	}{
+		{"Now", []string{"ak", "akowska"}},
+		{"now", []string{"ak", "akowska"}},
+		{"łu", []string{"czak"}},
+		{"Lu", []string{"czak"}},
+		{"kąk", []string{"ol"}},
+		{"kak", []string{"ol"}},
+		{"c-", []string{"1", "2"}},
+		{`\f`, []string{"ormat"}},
+		{"nowy", []string{}},
+		{"", nil},
+	}
+	for _, d := range data {
+		if got := c.Complete(d.prefix); !slices.Equal(got, d.want) {
+			t.Errorf("Complete(%#v) == %#v want %#v", d.prefix, got, d.want)
+		}
+	}
+	got, n := c.Do([]rune("kto to nowa"), 11)
+	if n != 4 || len(got) != 2 || string(got[0]) != "k" {
+		t.Errorf(`Do("kto to nowa", 11) == %q, %d want ["k" "kowska"], 4`,
+			got, n)
+	}
+}
@@ -2,12 +2,12 @@ package main
 
 import (
 	"database/sql"
-	"encoding/csv"
 	"errors"
 	"fmt"
 	"io"
 	"log"
 	"os"
+	"slices"
 	"strings"
 )
 
@@ -32,6 +32,11 @@ const (
 	Pokój      ColumnName = "pokój"
 	Telefon    ColumnName = "telefon"
 	Adres      ColumnName = "adres"
+	// Kolumny, które powstają z kolumny `osoba` za pomocą funkcji
+	// ParseOsoba i nie występują w pliku tekstowym `CSVFilename`
+	Tytuły   ColumnName = "tytuły"
+	Imiona   ColumnName = "imiona"
+	Nazwisko ColumnName = "nazwisko"
 )
 
 // Nazwy kolejnych kolumn tabeli Pracownicy
@@ -46,6 +51,31 @@ var ColumnNames = []ColumnName{
 	Adres,
 }
 
+// Nazwy kolumn tabeli Pracownicy, które powstają z kolumny `osoba`.
+// Tabela PracownicyFTS ma kolumny o tych samych nazwach zapisanych
+// bez znaków diakrytycznych
+var NameColumnNames = []ColumnName{
+	Tytuły,
+	Imiona,
+	Nazwisko,
+}
+
+// FTSColumnName zwraca nazwę tej kolumny tabeli PracownicyFTS, która
+// odpowiada kolumnie `c` tabeli Pracownicy
+func FTSColumnName(c ColumnName) ColumnName {
+	return ColumnName(ToASCIIString(string(c)))
+}
+
+// FTSColumnNames zwraca nazwy tych kolumn tabeli PracownicyFTS, które
+// odpowiadają kolumnom `cols` tabeli Pracownicy
+func FTSColumnNames(cols []ColumnName) []ColumnName {
+	ret := []ColumnName{}
+	for _, c := range cols {
+		ret = append(ret, FTSColumnName(c))
+	}
+	return ret
+}
+
 // main pobiera z wiersza poleceń kolejne pytania użytkownika,
 // wyrażone po polsku, przetwarza te pytania na zapytania do bazy
 // danych SQLite3 i wypisuje na standardowym wyjściu wyniki tych
@@ -53,35 +83,59 @@ var ColumnNames = []ColumnName{
 // program się kończy. Jeśli program został uruchomiony z flagą -init,
 // main najpierw tworzy nową bazę danych w pliku `DbFilename` i
 // zapisuje w niej dane z pliku tekstowego `CSVFilename`, a potem
-// działa tak, jak opisano powyżej
+// działa tak, jak opisano powyżej. Jeśli program został uruchomiony
+// z flagą -memory, main zamiast bazy danych używa katalogu
+// w pamięci, do którego wczytuje dane z pliku `CSVFilename`. Wiersze
+// zaczynające się ukośnikiem odwrotnym to polecenia, które opisuje
+// stała `HelpText`
 func main() {
-	var db *sql.DB
+	var dir Directory
 	if len(os.Args) > 1 && os.Args[1] == "-init" {
-		db = CreateDatabase(DbFilename)
-		FillDatabase(CSVFilename, db)
+		dir = SQLiteDirectory{CreateDatabase(DbFilename)}
+		dir.Index(ReadCSV(CSVFilename))
+	} else if len(os.Args) > 1 && os.Args[1] == "-memory" {
+		dir = NewMemoryDirectory()
+		dir.Index(ReadCSV(CSVFilename))
 	} else {
-		db = OpenDatabase(DbFilename)
+		dir = SQLiteDirectory{OpenDatabase(DbFilename)}
 	}
-	defer db.Close()
-	colsOfStems := GetColumnsOfStems(db)
+	defer dir.Close()
+	colsOfStems := dir.Vocabulary()
 
-	rl := CreateReadline("AGH> ")
+	completer := NewCompleter(dir.CompletionWords())
+	rl := CreateReadline("AGH> ", HistoryPath(), completer)
 	defer rl.Close()
+	format := TableFormat
 	for {
 		s, err := GetLine(rl)
 		if err == io.EOF {
 			break
 		}
-		s = TransformPhoneNumbers(s)
+		cmd, err := ParseCommand(s, format)
+		if err != nil {
+			fmt.Println(err)
+			continue
+		}
+		fmt.Print(cmd.Output)
+		if cmd.Quit {
+			break
+		}
+		if cmd.Question == "" {
+			format = cmd.Format
+			continue
+		}
+		s = TransformPhoneNumbers(cmd.Question)
 		as := ToASCIIString(s)
-		match, cols, err := ParseQuestion(as, colsOfStems)
+		terms, cols, err := ParseQuestionTerms(as, colsOfStems)
 		if err != nil {
 			fmt.Println(err)
 			continue
 		}
-		query := MakeQuery(match, cols)
-		res := ExecuteQuery(query, cols, db)
-		DisplayResult(res)
+		if cmd.Format == VCardFormat {
+			cols = VCardColumns
+		}
+		res := dir.Search(terms, cols)
+		Displays[cmd.Format](res)
 	}
 }
 
@@ -93,17 +147,20 @@ func main() {
 //
 // Każdy wiersz tabeli Pracownicy odpowiada 1 osobie, która jest
 // pracownikiem AGH i składa się z kolumn o nazwach wymienionych
-// w zmiennej globalnej `ColumnNames`
+// w zmiennych globalnych `ColumnNames` i `NameColumnNames`
 //
-// Każdy wiersz tabeli PracownicyFTS ma 2 pola:
+// Każdy wiersz tabeli PracownicyFTS ma 5 pól:
 // + pole rowid
 // + pole dane
+// + pola tytuly, imiona i nazwisko
 //
 // Pole rowid wiersza tabeli PracownicyFTS ma tę samą wartość, co pole
 // rowid odpowiedniego wiersza tabeli Pracownicy
 //
 // Pole dane wiersza tabeli PracownicyFTS zawiera tematy tych wyrazów,
-// które występują w odpowiednim wierszu tabeli Pracownicy
+// które występują w odpowiednim wierszu tabeli Pracownicy. Pola
+// tytuly, imiona i nazwisko zawierają tematy tych wyrazów, które
+// występują w polach tytuły, imiona i nazwisko
 //
 // Przykład:
 //
@@ -117,10 +174,16 @@ func main() {
 // pokój:      6.11
 // telefon:    12-328-99-99
 // adres:      ul. Kawiory 21
+// tytuły:     inż.
+// imiona:     Anna
+// nazwisko:   Kot
 //
 // Wiersz tabeli PracownicyFTS
-// rowid: 14
-// dane:  inz ann kot specjalist wydzial informatyk d-17 v 6.11 12-328-99-99 ul kawior 21
+// rowid:    14
+// dane:     inz ann kot specjalist wydzial informatyk d-17 v 6.11 12-328-99-99 ul kawior 21
+// tytuly:   inz
+// imiona:   ann
+// nazwisko: kot
 func CreateDatabase(dbFilename string) *sql.DB {
 	_ = os.Remove(dbFilename)
 	db := OpenDatabase(dbFilename)
@@ -133,67 +196,15 @@ docid INTEGER PRIMARY KEY
 , %s TEXT
 , %s TEXT
 , %s TEXT
-, %s TEXT)`, ToAnySlice(ColumnNames)...)
-	Execute(db, `CREATE VIRTUAL TABLE PracownicyFTS USING fts5(dane)`)
+, %s TEXT
+, %s TEXT
+, %s TEXT
+, %s TEXT)`, ToAnySlice(slices.Concat(ColumnNames, NameColumnNames))...)
+	Execute(db, `CREATE VIRTUAL TABLE PracownicyFTS USING fts5(
+dane, %s, %s, %s)`, ToAnySlice(FTSColumnNames(NameColumnNames))...)
 	return db
 }
 
-// FillDatabase zapisuje w bazie danych `db` dane z pliku tekstowego o
-// nazwie `csvFilename`. Każdy wiersz tego pliku zawiera te same pola,
-// co tabela Pracownicy, rozdzielone przecinkami
-func FillDatabase(csvFilename string, db *sql.DB) {
-	tx := BeginTransaction(db)
-	insertStmt := PrepareStatement(
-		tx, `INSERT INTO Pracownicy(%s,%s,%s,%s,%s,%s,%s,%s)
-VALUES (?,?,?,?,?,?,?,?)`, ToAnySlice(ColumnNames)...)
-	insertFTSStmt := PrepareStatement(
-		tx, `INSERT INTO PracownicyFTS(rowid,dane) VALUES (?,?)`)
-	file := OpenFile(csvFilename)
-	defer file.Close()
-
-	csvFile := csv.NewReader(file)
-	firstRecord := true
-	header := []ColumnName{}
-	for {
-		rec, err := ReadCsvRecord(csvFile)
-		if err == io.EOF {
-			break
-		}
-		if firstRecord {
-			for _, c := range rec {
-				header = append(header, ColumnName(c))
-			}
-			firstRecord = false
-			continue
-		}
-		row := map[ColumnName]string{}
-		for i, c := range header {
-			row[c] = rec[i]
-		}
-		rowid := ExecuteStatement(
-			insertStmt,
-			row[Osoba], row[Stanowisko], row[Jednostka],
-			row[Budynek], row[Piętro], row[Pokój],
-			TransformPhoneNumbers(row[Telefon]),
-			row[Adres])
-		stems := []ASCIIStem{}
-		for _, c := range ColumnNames {
-			as := ToASCIIString(row[c])
-			// Nie usuwaj piętra I
-			ss := ASCIIStringToASCIIStemSlice(as, c != Piętro)
-			stems = append(stems, ss...)
-		}
-		if strings.HasPrefix(row[Jednostka], "Wydział ") {
-			stems = append(stems,
-				AbbreviateFacultyName(row[Jednostka], true))
-			stems = append(stems,
-				AbbreviateFacultyName(row[Jednostka], false))
-		}
-		ExecuteStatement(insertFTSStmt, rowid, JoinASCIIStems(stems))
-	}
-	CommitTransaction(tx)
-}
-
 // GetColumnsOfStems zwraca mapę tematów wyrazów pochodzących z tabeli
 // Pracownicy na zbiory tych kolumn, w których występują te wyrazy
 //
@@ -219,23 +230,17 @@ func GetColumnsOfStems(db *sql.DB) map[ASCIIStem]map[ColumnName]bool {
 		ToAnySlice(ColumnNames)...)
 	for rows.Next() {
 		ScanRow(rows, args...)
+		row := map[ColumnName]string{}
 		for i, c := range ColumnNames {
-			as := ToASCIIString(rec[i])
-			// Nie usuwaj piętra I
-			stems := ASCIIStringToASCIIStemSlice(as, c != Piętro)
-			for _, stem := range stems {
-				AddStemColumn(&ret, stem, c)
-			}
+			row[c] = rec[i]
 		}
+		AddVocabulary(&ret, row, "")
 	}
 	rows = Query(db, `SELECT dane FROM PracownicyFTS`)
 	for rows.Next() {
 		var dane string
 		ScanRow(rows, &dane)
-		stems := strings.Fields(dane)
-		for _, stem := range stems {
-			AddStemColumn(&ret, ASCIIStem(stem), Jednostka)
-		}
+		AddVocabulary(&ret, nil, dane)
 	}
 	return ret
 }
@@ -271,6 +276,35 @@ var Replacements = map[ASCIIStem]ASCIIStem{
 	"gmach":       "a-0",
 }
 
+// Tematy wyrazów, po których następuje wartość pola tabeli
+// PracownicyFTS, np. "o nazwisku Nowak" lub "o imieniu Anna"
+var StemsToNameColumns = map[ASCIIStem]ColumnName{
+	"nazwisk": Nazwisko,
+	"imien":   Imiona,
+}
+
+// Wyrazy, po których następuje wartość pola tabeli PracownicyFTS,
+// a których tematy są zbyt krótkie, żeby trafić do mapy
+// `StemsToNameColumns`: temat "im" wyrazu "imię" to także zaimek
+// "im"
+var WordsToNameColumns = map[ASCIIWord]ColumnName{
+	"imie": Imiona,
+}
+
+// Tematy tytułów naukowych i zawodowych po zamianie przez
+// `Replacements`. Te tematy są wyszukiwane tylko w polu tytuly
+// tabeli PracownicyFTS
+var TitleStems = map[ASCIIStem]bool{
+	"lic":   true,
+	"inz":   true,
+	"mgr":   true,
+	"dr":    true,
+	"hab":   true,
+	"prof":  true,
+	"zw":    true,
+	"nadzw": true,
+}
+
 var StemsToColumnNames = map[ASCIIStem][]ColumnName{
 	"stanowisk": []ColumnName{Stanowisko},
 	"funkcj":    []ColumnName{Stanowisko},
@@ -287,13 +321,6 @@ var StemsToColumnNames = map[ASCIIStem][]ColumnName{
 	"ulic":      []ColumnName{Adres},
 }
 
-type Conjunction string
-
-const (
-	And Conjunction = "AND"
-	Not Conjunction = "NOT"
-)
-
 // ParseQuestion przetwarza pytanie `as`, wyrażone po polsku, na
 // łańcuch i na nazwy kolumn. Łańcuch opisuje te wartości pól tabeli
 // Pracownicy, które zna użytkownik. Nazwy kolumn nazywają te kolumny
@@ -301,13 +328,36 @@ const (
 func ParseQuestion(
 	as ASCIIString,
 	colsOfStems map[ASCIIStem]map[ColumnName]bool) (string, []ColumnName, error) {
-	conj := And
+	terms, cols, err := ParseQuestionTerms(as, colsOfStems)
+	if err != nil {
+		return "", nil, err
+	}
+	return MatchString(terms), cols, nil
+}
+
+// ParseQuestionTerms przetwarza pytanie `as`, wyrażone po polsku, na
+// człony i na nazwy kolumn. Człony opisują te wartości pól tabeli
+// Pracownicy, które zna użytkownik. Nazwy kolumn nazywają te kolumny
+// tabeli Pracownicy, których zawartość chce poznać użytkownik
+//
+// Tytuły naukowe są wyszukiwane tylko w polu tytuly tabeli
+// PracownicyFTS. Wyraz, który następuje po wyrazie "nazwisko" lub
+// "imię", jest wyszukiwany tylko w polu nazwisko lub imiona
+func ParseQuestionTerms(
+	as ASCIIString,
+	colsOfStems map[ASCIIStem]map[ColumnName]bool) ([]Term, []ColumnName, error) {
+	not := false
 	cols := map[ColumnName]bool{Osoba: true}
-	parts := map[Conjunction][]string{}
+	terms := []Term{}
+	nameCol := ColumnName("")
 	for _, s := range SplitASCIIString(as) {
 		w := ToASCIIWord(s)
 		if Negations[w] {
-			conj = Not
+			not = true
+			continue
+		}
+		if c := WordsToNameColumns[w]; c != "" {
+			nameCol = c
 			continue
 		}
 		stems := []ASCIIStem{}
@@ -315,7 +365,9 @@ func ParseQuestion(
 			if repl := Replacements[stem]; repl != "" {
 				stem = repl
 			}
-			if colNames := StemsToColumnNames[stem]; colNames != nil {
+			if c := StemsToNameColumns[stem]; c != "" {
+				nameCol = c
+			} else if colNames := StemsToColumnNames[stem]; colNames != nil {
 				for _, c := range colNames {
 					cols[c] = true
 				}
@@ -326,18 +378,18 @@ func ParseQuestion(
 				stems = append(stems, stem)
 			}
 		}
+		if titles := FilterTitleStems(stems); nameCol == "" && len(titles) > 0 {
+			stems = titles
+			nameCol = Tytuły
+		}
 		if len(stems) > 0 {
-			parts[conj] = append(
-				parts[conj], JoinQuotedStems(stems, ` OR `))
-			conj = And
+			terms = append(terms, Term{stems, nameCol, not})
+			nameCol = ""
+			not = false
 		}
 	}
-	if len(parts[And]) == 0 {
-		return "", nil, errors.New("W Twoim pytaniu brak konkretów")
-	}
-	retMatch := strings.Join(parts[And], " AND ")
-	if len(parts[Not]) > 0 {
-		retMatch += " NOT " + strings.Join(parts[Not], " NOT ")
+	if !slices.ContainsFunc(terms, func(t Term) bool { return !t.Not }) {
+		return nil, nil, errors.New("W Twoim pytaniu brak konkretów")
 	}
 	retCols := []ColumnName{}
 	for _, c := range ColumnNames {
@@ -345,7 +397,19 @@ func ParseQuestion(
 			retCols = append(retCols, c)
 		}
 	}
-	return retMatch, retCols, nil
+	return terms, retCols, nil
+}
+
+// FilterTitleStems zwraca te elementy wycinka `stems`, które są
+// tematami tytułów naukowych lub zawodowych
+func FilterTitleStems(stems []ASCIIStem) []ASCIIStem {
+	ret := []ASCIIStem{}
+	for _, s := range stems {
+		if TitleStems[s] {
+			ret = append(ret, s)
+		}
+	}
+	return ret
 }
 
 // MakeQuery tworzy zapytanie w języku SQL z łańcucha `match` i z nazw
@@ -356,7 +420,7 @@ func MakeQuery(match string, cols []ColumnName) string {
 	return fmt.Sprintf(`SELECT %s
 FROM Pracownicy JOIN PracownicyFTS
 ON Pracownicy.rowid = PracownicyFTS.rowid
-WHERE dane MATCH '%s'`, strings.Join(ToStringSlice(cols), ", "), match)
+WHERE PracownicyFTS MATCH '%s'`, strings.Join(ToStringSlice(cols), ", "), match)
 }
 
 // ExecuteQuery zwraca wynik zapytania `q` do bazy danych `db`. Wynik
@@ -368,24 +432,18 @@ WHERE dane MATCH '%s'`, strings.Join(ToStringSlice(cols), ", "), match)
 // zapytania `q`. Pierwszy element każdego wycinka złożonego z
 // łańcuchów to jego nagłówek
 func ExecuteQuery(q string, cols []ColumnName, db *sql.DB) [][]string {
-	ret := [][]string{[]string{"lp"}}
-	for _, c := range cols {
-		ret = append(ret, []string{string(c)})
-	}
+	ret := NewResult(cols)
 	row, args := MakeStringSliceAndAnySlice(len(cols))
 	rows, err := db.Query(q)
 	if err != nil {
 		log.Fatal(err)
 	}
-	for n := 1; rows.Next(); n++ {
+	for rows.Next() {
 		err := rows.Scan(args...)
 		if err != nil {
 			log.Fatal(err)
 		}
-		ret[0] = append(ret[0], fmt.Sprintf("%d", n))
-		for i, c := range row {
-			ret[i+1] = append(ret[i+1], c)
-		}
+		AppendResultRow(ret, row)
 	}
 	return ret
 }
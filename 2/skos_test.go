@@ -13,7 +13,7 @@ func TestParseQuestion(t *testing.T) {
 	}{
 		{
 			"jacy profesorowie pracuja w budynku c-1?",
-			`("prof") AND ("c-1")`,
+			`tytuly : ("prof") AND ("c-1")`,
 			[]ColumnName{Osoba, Budynek},
 		},
 		{
@@ -23,19 +23,34 @@ func TestParseQuestion(t *testing.T) {
 		},
 		{
 			"czy znasz kogos o imieniu filip?",
-			`("filip")`,
+			`imiona : ("filip")`,
 			[]ColumnName{Osoba},
 		},
 		{
 			"którzy doktorzy z wydziału chemii nie sa habilitowani?",
-			`("dr") AND ("chem") NOT ("hab")`,
+			`tytuly : ("dr") AND ("chem") NOT tytuly : ("hab")`,
 			[]ColumnName{Osoba, Jednostka},
 		},
 		{
 			"jakich znasz magistrow nowakow w budynku c-1?",
-			`("mgr") AND ("nowakow" OR "nowak") AND ("c-1")`,
+			`tytuly : ("mgr") AND ("nowakow" OR "nowak") AND ("c-1")`,
 			[]ColumnName{Osoba, Budynek},
 		},
+		{
+			"jacy profesorowie maja na nazwisko nowak?",
+			`tytuly : ("prof") AND nazwisko : ("nowak")`,
+			[]ColumnName{Osoba},
+		},
+		{
+			"kto ma na imie filip?",
+			`imiona : ("filip")`,
+			[]ColumnName{Osoba},
+		},
+		{
+			"kto im pomogl, nowak?",
+			`("nowak")`,
+			[]ColumnName{Osoba},
+		},
 		{
 			"kto nie jest habilitowany?",
 			"",
@@ -70,7 +85,7 @@ func TestMakeQuery(t *testing.T) {
 	want := `SELECT osoba, budynek
 FROM Pracownicy JOIN PracownicyFTS
 ON Pracownicy.rowid = PracownicyFTS.rowid
-WHERE dane MATCH '("mgr") AND ("nowakow" OR "nowak") AND ("c-1")'`
+WHERE PracownicyFTS MATCH '("mgr") AND ("nowakow" OR "nowak") AND ("c-1")'`
 	if got := MakeQuery(match, cols); got != want {
 		t.Errorf("MakeQuery(%#v, %#v) == %#v want %#v",
 			match, cols, got, want)
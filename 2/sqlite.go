@@ -0,0 +1,73 @@
+package main
+
+import (
+	"database/sql"
+	"maps"
+	"slices"
+)
+
+// SQLiteDirectory implementuje katalog pracowników AGH za pomocą bazy
+// danych SQLite3, która zawiera tabele Pracownicy i PracownicyFTS
+// opisane przy funkcji CreateDatabase
+type SQLiteDirectory struct {
+	db *sql.DB
+}
+
+// Index zapisuje wiersze `rows` w tabelach Pracownicy i PracownicyFTS
+func (d SQLiteDirectory) Index(rows []map[ColumnName]string) {
+	tx := BeginTransaction(d.db)
+	insertStmt := PrepareStatement(
+		tx, `INSERT INTO Pracownicy(%s,%s,%s,%s,%s,%s,%s,%s,%s,%s,%s)
+VALUES (?,?,?,?,?,?,?,?,?,?,?)`,
+		ToAnySlice(slices.Concat(ColumnNames, NameColumnNames))...)
+	insertFTSStmt := PrepareStatement(
+		tx, `INSERT INTO PracownicyFTS(rowid,dane,%s,%s,%s)
+VALUES (?,?,?,?,?)`, ToAnySlice(FTSColumnNames(NameColumnNames))...)
+	for _, row := range rows {
+		rowid := ExecuteStatement(
+			insertStmt,
+			row[Osoba], row[Stanowisko], row[Jednostka],
+			row[Budynek], row[Piętro], row[Pokój],
+			TransformPhoneNumbers(row[Telefon]),
+			row[Adres], row[Tytuły], row[Imiona], row[Nazwisko])
+		fts := FTSFields(row)
+		ExecuteStatement(insertFTSStmt, rowid, fts[Dane],
+			fts[Tytuły], fts[Imiona], fts[Nazwisko])
+	}
+	CommitTransaction(tx)
+}
+
+// Search wysyła do bazy danych zapytanie złożone z członów `terms`
+// i zwraca wynik tego zapytania
+func (d SQLiteDirectory) Search(terms []Term, cols []ColumnName) [][]string {
+	return ExecuteQuery(MakeQuery(MatchString(terms), cols), cols, d.db)
+}
+
+// Vocabulary zwraca wynik funkcji GetColumnsOfStems
+func (d SQLiteDirectory) Vocabulary() map[ASCIIStem]map[ColumnName]bool {
+	return GetColumnsOfStems(d.db)
+}
+
+// CompletionWords zwraca posortowane nazwiska, numery budynków
+// i skróty nazw wydziałów zapisane w tabeli Pracownicy
+func (d SQLiteDirectory) CompletionWords() []string {
+	cols := []ColumnName{Nazwisko, Budynek, Jednostka}
+	rec, args := MakeStringSliceAndAnySlice(len(cols))
+	words := map[string]bool{}
+	rows := Query(d.db, `SELECT %s,%s,%s FROM Pracownicy`,
+		ToAnySlice(cols)...)
+	for rows.Next() {
+		ScanRow(rows, args...)
+		row := map[ColumnName]string{}
+		for i, c := range cols {
+			row[c] = rec[i]
+		}
+		AddCompletionWords(words, row)
+	}
+	return slices.Sorted(maps.Keys(words))
+}
+
+// Close zamyka bazę danych
+func (d SQLiteDirectory) Close() {
+	d.db.Close()
+}
@@ -0,0 +1,12 @@
+//go:build cgo && !purego
+
+package main
+
+import (
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// Nazwa sterownika bazy danych SQLite3, który wymaga cgo. Moduł FTS5
+// jest dostępny w tym sterowniku po kompilacji z flagą
+// -tags sqlite_fts5
+const SQLiteDriver = "sqlite3"
@@ -0,0 +1,12 @@
+//go:build !cgo || purego
+
+package main
+
+import (
+	_ "modernc.org/sqlite"
+)
+
+// Nazwa sterownika bazy danych SQLite3 napisanego w czystym Go. Ten
+// sterownik jest używany, jeśli program jest kompilowany bez cgo
+// (CGO_ENABLED=0) lub z flagą -tags purego
+const SQLiteDriver = "sqlite"
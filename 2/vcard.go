@@ -0,0 +1,141 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Kolumny tabeli Pracownicy, z których powstają wizytówki vCard
+var VCardColumns = []ColumnName{
+	Osoba,
+	Stanowisko,
+	Jednostka,
+	Telefon,
+	Adres,
+}
+
+// escapeVCardText poprzedza ukośnikiem odwrotnym te znaki łańcucha
+// `s`, które mają specjalne znaczenie w wartościach tekstowych
+// wizytówki vCard (RFC 6350, sekcja 3.4)
+var escapeVCardText = strings.NewReplacer(
+	`\`, `\\`,
+	`,`, `\,`,
+	`;`, `\;`,
+	"\n", `\n`,
+).Replace
+
+// foldVCardLine dzieli wiersz `line` na wiersze o długości co najwyżej
+// 75 bajtów, nie rozcinając znaków UTF-8. Każdy wiersz oprócz
+// pierwszego zaczyna się spacją (RFC 6350, sekcja 3.2)
+func foldVCardLine(line string) string {
+	b := strings.Builder{}
+	width := 75
+	for len(line) > width {
+		n := width
+		for n > 0 && line[n]&0xC0 == 0x80 {
+			n--
+		}
+		b.WriteString(line[:n])
+		b.WriteString("\r\n ")
+		line = line[n:]
+		width = 74
+	}
+	b.WriteString(line)
+	b.WriteString("\r\n")
+	return b.String()
+}
+
+// VCard zwraca wizytówkę w formacie vCard 4.0 z danymi osoby `row`.
+// Tytuły naukowe z pola `osoba` trafiają do pól HONORIFIC-PREFIX
+// i HONORIFIC-SUFFIX właściwości N, a stanowisko — do właściwości
+// TITLE. Puste pola wiersza `row` są pomijane
+//
+// Przykład:
+//
+// Wiersz
+// osoba:      dr inż. Anna Kot
+// stanowisko: adiunkt
+// jednostka:  Wydział Informatyki, Katedra Informatyki
+// telefon:    12-328-99-99
+// adres:      ul. Kawiory 21
+//
+// Wizytówka
+// BEGIN:VCARD
+// VERSION:4.0
+// FN:Anna Kot
+// N:Kot;Anna;;dr inż.;
+// TITLE:adiunkt
+// ORG:AGH;Wydział Informatyki;Katedra Informatyki
+// TEL;VALUE=uri;TYPE=work,voice:tel:+48-12-328-99-99
+// ADR;TYPE=work:;;ul. Kawiory 21;;;;
+// END:VCARD
+func VCard(row map[ColumnName]string) string {
+	p := ParseOsoba(row[Osoba])
+	lines := []string{
+		"BEGIN:VCARD",
+		"VERSION:4.0",
+		"FN:" + escapeVCardText(p.Name()),
+		fmt.Sprintf("N:%s;%s;;%s;%s",
+			escapeVCardText(p.Surname),
+			escapeVCardText(strings.Join(p.GivenNames, " ")),
+			escapeVCardText(strings.Join(p.Titles, " ")),
+			escapeVCardText(p.PostNominal)),
+	}
+	if row[Stanowisko] != "" {
+		lines = append(lines, "TITLE:"+escapeVCardText(row[Stanowisko]))
+	}
+	if row[Jednostka] != "" {
+		org := []string{"AGH"}
+		for _, u := range strings.Split(row[Jednostka], ",") {
+			org = append(org, escapeVCardText(strings.TrimSpace(u)))
+		}
+		lines = append(lines, "ORG:"+strings.Join(org, ";"))
+	}
+	if row[Telefon] != "" {
+		lines = append(lines,
+			"TEL;VALUE=uri;TYPE=work,voice:tel:+48-"+row[Telefon])
+	}
+	if row[Adres] != "" {
+		lines = append(lines,
+			"ADR;TYPE=work:;;"+escapeVCardText(row[Adres])+";;;;")
+	}
+	lines = append(lines, "END:VCARD")
+	b := strings.Builder{}
+	for _, l := range lines {
+		b.WriteString(foldVCardLine(l))
+	}
+	return b.String()
+}
+
+// ResultRows zmienia wynik zapytania `res`, zwrócony przez funkcję
+// ExecuteQuery, na wycinek wierszy. Każdy wiersz odwzorowuje nazwy
+// kolumn na wartości pól
+func ResultRows(res [][]string) []map[ColumnName]string {
+	if len(res) == 0 {
+		return nil
+	}
+	rows := []map[ColumnName]string{}
+	for y := 1; y < len(res[0]); y++ {
+		row := map[ColumnName]string{}
+		for _, col := range res[1:] {
+			row[ColumnName(col[0])] = col[y]
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// DisplayVCards wypisuje na standardowym wyjściu wynik zapytania
+// `res` jako ciąg wizytówek vCard. Jeśli `res` składa się tylko
+// z nagłówków kolumn, DisplayVCards wypisuje komunikat "Nie znam
+// takich osób"
+func DisplayVCards(res [][]string) {
+	rows := ResultRows(res)
+	if len(rows) == 0 {
+		fmt.Println("Nie znam takich osób")
+		return
+	}
+	for _, row := range rows {
+		fmt.Print(VCard(row))
+	}
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestVCard(t *testing.T) {
+	row := map[ColumnName]string{
+		Osoba:      "dr hab. inż. Anna Kot, prof. AGH",
+		Stanowisko: "adiunkt",
+		Jednostka:  "Wydział Informatyki, Katedra Informatyki",
+		Telefon:    "12-328-99-99",
+		Adres:      "ul. Kawiory 21",
+	}
+	want := strings.Join([]string{
+		"BEGIN:VCARD",
+		"VERSION:4.0",
+		"FN:Anna Kot",
+		"N:Kot;Anna;;dr hab. inż.;prof. AGH",
+		"TITLE:adiunkt",
+		"ORG:AGH;Wydział Informatyki;Katedra Informatyki",
+		"TEL;VALUE=uri;TYPE=work,voice:tel:+48-12-328-99-99",
+		"ADR;TYPE=work:;;ul. Kawiory 21;;;;",
+		"END:VCARD",
+		"",
+	}, "\r\n")
+	if got := VCard(row); got != want {
+		t.Errorf("VCard(%#v) == %#v want %#v", row, got, want)
+	}
+}
+
+func TestFoldVCardLine(t *testing.T) {
+	in := "TITLE:" + strings.Repeat("żółw ", 20)
+	got := foldVCardLine(in)
+	lines := strings.Split(strings.TrimSuffix(got, "\r\n"), "\r\n ")
+	if joined := strings.Join(lines, ""); joined != in {
+		t.Errorf("foldVCardLine(%#v) == %#v; unfolded %#v", in, got, joined)
+	}
+	for _, l := range lines {
+		if len(l) > 75 || !utf8.ValidString(l) {
+			t.Errorf("foldVCardLine(%#v) contains line %#v", in, l)
+		}
+	}
+}
+
+func TestResultRows(t *testing.T) {
+	res := [][]string{
+		{"lp", "1", "2"},
+		{"osoba", "Anna Kot", "Jan Nowak"},
+		{"telefon", "12-328-99-99", ""},
+	}
+	want := []map[ColumnName]string{
+		{Osoba: "Anna Kot", Telefon: "12-328-99-99"},
+		{Osoba: "Jan Nowak", Telefon: ""},
+	}
+	if got := ResultRows(res); !reflect.DeepEqual(got, want) {
+		t.Errorf("ResultRows(%#v) == %#v want %#v", res, got, want)
+	}
+}
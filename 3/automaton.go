@@ -0,0 +1,100 @@
+package matching
+
+import (
+	"iter"
+)
+
+// KMPAutomaton to deterministyczny automat skończony, który
+// rozpoznaje wystąpienia wzorca. Stan automatu to długość tego
+// najdłuższego prefiksu wzorca, który jest sufiksem przetworzonego
+// tekstu. W odróżnieniu od funkcji KMP automat nie korzysta z funkcji
+// prefiksowej podczas wyszukiwania, tylko z tablicy przejść
+type KMPAutomaton struct {
+	pat []byte
+	// delta[j*256+c] == k*256, gdzie k to stan, do którego automat
+	// przechodzi ze stanu j po przetworzeniu bajtu c. Mnożenie
+	// stanów przez 256 skraca obliczenia w pętli wyszukiwania
+	delta []int32
+}
+
+// NewKMPAutomaton zwraca automat, który rozpoznaje wystąpienia wzorca
+// `pat`. Tablicę przejść oblicza za pomocą funkcji KMPPrefixFunction
+func NewKMPAutomaton(pat []byte) *KMPAutomaton {
+	n := len(pat)
+	p := KMPPrefixFunction(pat)
+	delta := make([]int32, (n+1)*256)
+	for j := 0; j <= n; j++ {
+		row := delta[j*256 : (j+1)*256]
+		if j > 0 {
+			// Przy niezgodności automat zachowuje się tak jak
+			// w stanie, który jest najdłuższym właściwym
+			// prefikso-sufiksem `pat[:j]`
+			copy(row, delta[p[j]*256:(p[j]+1)*256])
+		}
+		if j < n {
+			row[pat[j]] = int32(j+1) * 256
+		}
+	}
+	return &KMPAutomaton{pat, delta}
+}
+
+// Step zwraca stan, do którego automat przechodzi ze stanu `state`
+// po przetworzeniu bajtu `c`
+func (a *KMPAutomaton) Step(state int, c byte) int {
+	return int(a.delta[state*256+int(c)]) / 256
+}
+
+// Resume przetwarza tekst `text`, zaczynając od stanu `state`, i zwraca
+// stan po przetworzeniu tego tekstu. Wywołuje `output(i)` dla każdego
+// indeksu `i` wystąpienia wzorca względem początku `text`. Indeks `i`
+// może być ujemny, jeśli wystąpienie zaczyna się w tekście
+// przetworzonym wcześniej. Stan zwrócony po przetworzeniu jednego
+// fragmentu tekstu można przekazać do wywołania dla następnego
+// fragmentu. Początkowy stan automatu to 0. Pusty wzorzec zostaje
+// zgłoszony przed każdym bajtem `text`, czyli na pozycjach od 0 do
+// `len(text)-1`, więc każde wystąpienie w tekście podzielonym na
+// fragmenty zostaje zgłoszone raz, oprócz wystąpienia na końcu
+// całego tekstu
+func (a *KMPAutomaton) Resume(state int, text []byte, output func(int)) int {
+	n := len(a.pat)
+	if n == 0 {
+		for i := range text {
+			output(i)
+		}
+		return 0
+	}
+	s := int32(state) * 256
+	for i, c := range text {
+		s = a.delta[int(s)+int(c)]
+		if int(s) == n*256 {
+			output(i - n + 1)
+		}
+	}
+	return int(s) / 256
+}
+
+func (a *KMPAutomaton) All(text []byte) iter.Seq[int] {
+	if len(a.pat) == 0 {
+		return CompileNaive(a.pat).All(text)
+	}
+	return func(yield func(int) bool) {
+		n := len(a.pat)
+		s := int32(0)
+		for i, c := range text {
+			s = a.delta[int(s)+int(c)]
+			if int(s) == n*256 && !yield(i-n+1) {
+				return
+			}
+		}
+	}
+}
+
+func (a *KMPAutomaton) FindAll(text []byte, output func(int)) {
+	forEach(a.All(text), output)
+}
+
+// CompileKMPAutomaton zwraca automat utworzony przez funkcję
+// NewKMPAutomaton jako obiekt typu Matcher
+func CompileKMPAutomaton(pat []byte) Matcher {
+	return NewKMPAutomaton(pat)
+}
@@ -0,0 +1,92 @@
+package matching
+
+import (
+	"bytes"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestKMPAutomatonResume(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for range 500 {
+		k := 1 + r.Intn(3)
+		pat := randomBytes(r, 1+r.Intn(6), k)
+		text := randomBytes(r, r.Intn(200), k)
+		a := NewKMPAutomaton(pat)
+		// Przetwórz tekst w losowych fragmentach, zachowując stan
+		got := []int{}
+		state := 0
+		for offset := 0; offset < len(text); /**/ {
+			end := min(offset+r.Intn(10), len(text))
+			state = a.Resume(state, text[offset:end], func(i int) {
+				got = append(got, offset+i)
+			})
+			offset = end
+		}
+		if want := indices(pat, text); !slices.Equal(got, want) {
+			t.Errorf("Resume(%#v, %#v) == %#v want %#v",
+				string(pat), string(text), got, want)
+		}
+		// Stan to długość najdłuższego prefiksu wzorca, który jest
+		// sufiksem tekstu
+		want := 0
+		for j := min(len(pat), len(text)); j > 0; j-- {
+			if bytes.HasSuffix(text, pat[:j]) && j < len(pat) ||
+				j == len(pat) && bytes.HasSuffix(text, pat) {
+				want = j
+				break
+			}
+		}
+		if state != want {
+			t.Errorf("Resume(%#v, %#v) returned state %d want %d",
+				string(pat), string(text), state, want)
+		}
+	}
+}
+
+func TestKMPAutomatonResumeEmpty(t *testing.T) {
+	a := NewKMPAutomaton([]byte{})
+	for _, text := range []string{"", "a", "abc", "abcdef"} {
+		// Dwa fragmenty tekstu
+		for split := range len(text) + 1 {
+			got := []int{}
+			state := a.Resume(0, []byte(text[:split]), func(i int) {
+				got = append(got, i)
+			})
+			state = a.Resume(state, []byte(text[split:]), func(i int) {
+				got = append(got, split+i)
+			})
+			// Wszystkie wystąpienia oprócz tego na końcu tekstu
+			all := slices.Collect(a.All([]byte(text)))
+			if want := all[:len(all)-1]; !slices.Equal(got, want) ||
+				state != 0 {
+				t.Errorf("Resume(\"\", %#v, %#v) == %#v, %d want %#v, 0",
+					text[:split], text[split:], got, state, want)
+			}
+		}
+	}
+}
+
+// Wzorzec i tekst, dla których funkcja KMP wielokrotnie korzysta
+// z funkcji prefiksowej przy każdej niezgodności
+var (
+	failurePattern = []byte("aaaaaaaaab")
+	failureText    = bytes.Repeat([]byte("aaaaaaaaac"), 1<<17)
+)
+
+func BenchmarkKMPFailureLinks(b *testing.B) {
+	m := CompileKMP(failurePattern)
+	b.SetBytes(int64(len(failureText)))
+	for b.Loop() {
+		m.FindAll(failureText, func(int) {})
+	}
+}
+
+func BenchmarkKMPAutomaton(b *testing.B) {
+	m := NewKMPAutomaton(failurePattern)
+	b.SetBytes(int64(len(failureText)))
+	for b.Loop() {
+		m.FindAll(failureText, func(int) {})
+	}
+}
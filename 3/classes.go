@@ -0,0 +1,240 @@
+package matching
+
+import (
+	"errors"
+	"fmt"
+	"iter"
+)
+
+// Opcje funkcji CompileShiftOrPattern
+type PatternFlags int
+
+const (
+	// IgnoreCase sprawia, że wielkie i małe litery ASCII we wzorcu
+	// pasują do siebie nawzajem
+	IgnoreCase PatternFlags = 1 << iota
+	// IUPAC sprawia, że litery we wzorcu to kody nukleotydów IUPAC,
+	// na przykład R to [AG], a N to [ACGT]
+	IUPAC
+)
+
+// IUPACCodes odwzorowuje kody nukleotydów IUPAC na nukleotydy
+var IUPACCodes = map[byte]string{
+	'A': "A", 'C': "C", 'G': "G", 'T': "T", 'U': "U",
+	'R': "AG", 'Y': "CT", 'S': "CG", 'W': "AT", 'K': "GT", 'M': "AC",
+	'B': "CGT", 'D': "AGT", 'H': "ACT", 'V': "ACG", 'N': "ACGT",
+}
+
+// ShiftOrPattern to wzorzec przetworzony przez funkcję
+// CompileShiftOrPattern
+type ShiftOrPattern struct {
+	m [256]uint64
+	// Bit j jest równy 0, jeśli j-ty element wzorca jest opcjonalny
+	opt uint64
+	// Liczba elementów wzorca
+	n int
+	// Najdłuższy ciąg kolejnych opcjonalnych elementów wzorca
+	maxOptRun int
+}
+
+// byteSet to zbiór bajtów, do których pasuje jeden element wzorca
+type byteSet [256]bool
+
+// add dodaje do zbioru bajt `c` lub, jeśli `flags` zawiera IUPAC albo
+// IgnoreCase, wszystkie bajty, które oznacza `c`
+func (bs *byteSet) add(c byte, flags PatternFlags) {
+	cs := []byte{c}
+	if flags&IUPAC != 0 {
+		if nucleotides, ok := IUPACCodes[toUpper(c)]; ok {
+			cs = []byte(nucleotides)
+		}
+	}
+	for _, c := range cs {
+		bs[c] = true
+		if flags&IgnoreCase != 0 {
+			bs[toUpper(c)] = true
+			bs[toLower(c)] = true
+		}
+	}
+}
+
+// toUpper zamienia małą literę ASCII na wielką
+func toUpper(c byte) byte {
+	if 'a' <= c && c <= 'z' {
+		return c - 'a' + 'A'
+	}
+	return c
+}
+
+// toLower zamienia wielką literę ASCII na małą
+func toLower(c byte) byte {
+	if 'A' <= c && c <= 'Z' {
+		return c - 'A' + 'a'
+	}
+	return c
+}
+
+// parseClass wczytuje z początku łańcucha `expr` klasę znaków bez
+// otwierającego nawiasu `[`. Zwraca tę klasę i liczbę wczytanych
+// bajtów łańcucha `expr`
+func parseClass(expr string, flags PatternFlags) (byteSet, int, error) {
+	bs := byteSet{}
+	i := 0
+	negate := i < len(expr) && expr[i] == '^'
+	if negate {
+		i++
+	}
+	for first := true; ; first = false {
+		if i >= len(expr) {
+			return bs, i, errors.New("brak zamykającego nawiasu ]")
+		}
+		if expr[i] == ']' && !first {
+			i++
+			break
+		}
+		lo := expr[i]
+		if lo == '\\' && i+1 < len(expr) {
+			i++
+			lo = expr[i]
+		}
+		i++
+		hi := lo
+		if i+1 < len(expr) && expr[i] == '-' && expr[i+1] != ']' {
+			hi = expr[i+1]
+			if hi == '\\' && i+2 < len(expr) {
+				i++
+				hi = expr[i+1]
+			}
+			i += 2
+			if hi < lo {
+				return bs, i, fmt.Errorf("niepoprawny zakres %c-%c", lo, hi)
+			}
+		}
+		for c := int(lo); c <= int(hi); c++ {
+			bs.add(byte(c), flags)
+		}
+	}
+	if negate {
+		for c := range bs {
+			bs[c] = !bs[c]
+		}
+	}
+	return bs, i, nil
+}
+
+// CompileShiftOrPattern przetwarza wzorzec `expr` dla algorytmu
+// Shift-Or. Wzorzec składa się z co najwyżej 64 elementów. Element
+// to bajt, `.` (dowolny bajt), klasa bajtów `[abc]`, `[a-z]` lub
+// `[^abc]`, albo bajt poprzedzony znakiem `\`. Element, po którym
+// następuje `?`, jest opcjonalny. Wzorzec musi zawierać co najmniej
+// jeden element, który nie jest opcjonalny
+func CompileShiftOrPattern(expr string, flags PatternFlags) (*ShiftOrPattern, error) {
+	elems := []byteSet{}
+	optional := []bool{}
+	for i := 0; i < len(expr); {
+		bs := byteSet{}
+		switch expr[i] {
+		case '.':
+			for c := range bs {
+				bs[c] = true
+			}
+			i++
+		case '[':
+			class, n, err := parseClass(expr[i+1:], flags)
+			if err != nil {
+				return nil, fmt.Errorf("%s we wzorcu %q", err, expr)
+			}
+			bs = class
+			i += 1 + n
+		case '?':
+			if len(elems) == 0 || optional[len(elems)-1] {
+				return nil, fmt.Errorf(
+					"nie ma czego powtarzać we wzorcu %q", expr)
+			}
+			optional[len(elems)-1] = true
+			i++
+			continue
+		case '\\':
+			if i+1 == len(expr) {
+				return nil, fmt.Errorf(
+					"ukośnik wsteczny na końcu wzorca %q", expr)
+			}
+			bs.add(expr[i+1], flags)
+			i += 2
+		default:
+			bs.add(expr[i], flags)
+			i++
+		}
+		elems = append(elems, bs)
+		optional = append(optional, false)
+	}
+	if len(elems) > 64 {
+		return nil, fmt.Errorf("wzorzec %q ma więcej niż 64 elementy", expr)
+	}
+	p := &ShiftOrPattern{n: len(elems), opt: ^uint64(0)}
+	for c := range p.m {
+		p.m[c] = ^uint64(0)
+	}
+	run := 0
+	for j, bs := range elems {
+		for c, ok := range bs {
+			if ok {
+				p.m[c] &^= setNthBit(j)
+			}
+		}
+		if optional[j] {
+			p.opt &^= setNthBit(j)
+			run++
+			p.maxOptRun = max(p.maxOptRun, run)
+		} else {
+			run = 0
+		}
+	}
+	if p.n == 0 || p.maxOptRun == p.n {
+		return nil, fmt.Errorf("wzorzec %q pasuje do pustego łańcucha", expr)
+	}
+	return p, nil
+}
+
+// skipOptional zeruje w masce `s` bity tych opcjonalnych elementów
+// wzorca, które można pominąć, bo wyzerowany jest bit poprzedniego
+// elementu lub są to początkowe elementy wzorca
+func (p *ShiftOrPattern) skipOptional(s uint64) uint64 {
+	for range p.maxOptRun {
+		s &= (s << 1) | p.opt
+	}
+	return s
+}
+
+// All zwraca iterator po takich indeksach `i`, że pewien wycinek
+// `text[...:i+1]` pasuje do wzorca `p`
+func (p *ShiftOrPattern) All(text []byte) iter.Seq[int] {
+	return func(yield func(int) bool) {
+		s := p.skipOptional(^uint64(0))
+		for i, c := range text {
+			s = p.skipOptional((s << 1) | p.m[c])
+			if nthBit(s, p.n-1) == 0 && !yield(i) {
+				return
+			}
+		}
+	}
+}
+
+// FindAll wywołuje `output(i)` dla każdego takiego indeksu `i`, że
+// pewien wycinek `text[...:i+1]` pasuje do wzorca `p`
+func (p *ShiftOrPattern) FindAll(text []byte, output func(int)) {
+	forEach(p.All(text), output)
+}
+
+// ShiftOrClasses wywołuje `output(i)` dla każdego takiego indeksu `i`,
+// że pewien wycinek `text[...:i+1]` pasuje do wzorca `expr` opisanego
+// przy funkcji CompileShiftOrPattern
+func ShiftOrClasses(expr string, flags PatternFlags, text []byte,
+	output func(int)) error {
+	p, err := CompileShiftOrPattern(expr, flags)
+	if err != nil {
+		return err
+	}
+	p.FindAll(text, output)
+	return nil
+}
@@ -0,0 +1,144 @@
+package matching
+
+import (
+	"math/rand"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// findAllEnds zwraca indeksy przekazane przez wzorzec `p` funkcji
+// `output`
+func findAllEnds(p *ShiftOrPattern, text string) []int {
+	r := []int{}
+	p.FindAll([]byte(text), func(i int) { r = append(r, i) })
+	return r
+}
+
+func TestShiftOrPattern(t *testing.T) {
+	data := []struct {
+		expr  string
+		flags PatternFlags
+		text  string
+		want  []int
+	}{
+		{"abc", 0, "xabcabc", []int{3, 6}},
+		{"a.c", 0, "abcadca\nc", []int{2, 5, 8}},
+		{"[bc]at", 0, "bat cat rat", []int{2, 6}},
+		{"[a-c]x", 0, "ax bx dx", []int{1, 4}},
+		{"[^a-c]x", 0, "ax bx dx", []int{7}},
+		{"[]]", 0, "a]", []int{1}},
+		{`a\.b`, 0, "a.b axb", []int{2}},
+		{`[\]-]`, 0, "a]-", []int{1, 2}},
+		{"colou?r", 0, "color colour colouur", []int{4, 11}},
+		{"a?b?c", 0, "c bc abc ac", []int{0, 3, 7, 10}},
+		{"ab?c?d", 0, "ad abd acd abcd", []int{1, 5, 9, 14}},
+		{"kot", IgnoreCase, "Kot KOT kOt pies", []int{2, 6, 10}},
+		{"[k-l]ot", IgnoreCase, "Kot LOT mot", []int{2, 6}},
+		{"GNNC", IUPAC, "GATCGTTAGCCC", []int{3, 11}},
+		{"RY", IUPAC, "AC GT CA", []int{1, 4}},
+		{"ry", IUPAC | IgnoreCase, "ac gt ca", []int{1, 4}},
+		{"[RY]", IUPAC, "ACGT", []int{0, 1, 2, 3}},
+	}
+	for _, d := range data {
+		p, err := CompileShiftOrPattern(d.expr, d.flags)
+		if err != nil {
+			t.Errorf("CompileShiftOrPattern(%#v) returned error %v",
+				d.expr, err)
+			continue
+		}
+		if got := findAllEnds(p, d.text); !slices.Equal(got, d.want) {
+			t.Errorf("FindAll(%#v, %#v) == %#v want %#v",
+				d.expr, d.text, got, d.want)
+		}
+		got := slices.Collect(p.All([]byte(d.text)))
+		if !slices.Equal(got, d.want) {
+			t.Errorf("All(%#v, %#v) == %#v want %#v",
+				d.expr, d.text, got, d.want)
+		}
+	}
+}
+
+func TestShiftOrPatternErrors(t *testing.T) {
+	data := []string{
+		"",
+		"a?",
+		"a?b?",
+		"?a",
+		"a??",
+		"[abc",
+		"[z-a]",
+		`ab\`,
+		strings.Repeat("a", 65),
+	}
+	for _, expr := range data {
+		if _, err := CompileShiftOrPattern(expr, 0); err == nil {
+			t.Errorf("CompileShiftOrPattern(%#v) returned no error", expr)
+		}
+	}
+}
+
+// Elementy wzorców, które mają to samo znaczenie w składni funkcji
+// CompileShiftOrPattern i w składni pakietu regexp
+var patternElements = []string{
+	"a", "b", "c", ".", "[ab]", "[^a]", "[a-c]", "[b-c]", `\.`,
+}
+
+func TestShiftOrPatternRegexp(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for range 1000 {
+		elems := []string{}
+		mandatory := false
+		for range 1 + r.Intn(6) {
+			e := patternElements[r.Intn(len(patternElements))]
+			if r.Intn(3) == 0 {
+				e += "?"
+			} else {
+				mandatory = true
+			}
+			elems = append(elems, e)
+		}
+		if !mandatory {
+			continue
+		}
+		expr := strings.Join(elems, "")
+		flags := PatternFlags(0)
+		prefix := "(?s)"
+		if r.Intn(2) == 0 {
+			flags = IgnoreCase
+			prefix = "(?is)"
+		}
+		re := regexp.MustCompile(prefix + "^(?:" + expr + ")$")
+		text := string(randomBytes(r, r.Intn(30), 3))
+		text = strings.Map(func(c rune) rune {
+			// Wielkie litery i kropki w tekście
+			switch r.Intn(6) {
+			case 0:
+				return c - 'a' + 'A'
+			case 1:
+				return '.'
+			}
+			return c
+		}, text)
+		want := []int{}
+		for i := range len(text) {
+			for j := 0; j <= i; j++ {
+				if re.MatchString(text[j : i+1]) {
+					want = append(want, i)
+					break
+				}
+			}
+		}
+		p, err := CompileShiftOrPattern(expr, flags)
+		if err != nil {
+			t.Errorf("CompileShiftOrPattern(%#v) returned error %v",
+				expr, err)
+			continue
+		}
+		if got := findAllEnds(p, text); !slices.Equal(got, want) {
+			t.Errorf("FindAll(%#v, %#v) with flags %d == %#v want %#v",
+				expr, text, flags, got, want)
+		}
+	}
+}
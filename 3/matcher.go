@@ -0,0 +1,44 @@
+package matching
+
+import (
+	"iter"
+)
+
+// Matcher wyszukuje w tekście wzorzec, który został przetworzony
+// wstępnie podczas tworzenia tego obiektu. Jeden obiekt można
+// wykorzystać do przeszukania wielu tekstów, także współbieżnie
+type Matcher interface {
+	// FindAll wywołuje `output(i)` dla każdego takiego `i`, że
+	// `slices.Equal(text[i:i+len(pat)], pat)`, gdzie `pat` to
+	// wzorzec, z którego powstał ten obiekt
+	FindAll(text []byte, output func(int))
+	// All zwraca iterator po tych samych indeksach, które FindAll
+	// przekazuje funkcji `output`. Przerwanie pętli range kończy
+	// wyszukiwanie
+	All(text []byte) iter.Seq[int]
+}
+
+// forEach wywołuje `output(i)` dla każdego elementu `i` iteratora `seq`
+func forEach(seq iter.Seq[int], output func(int)) {
+	for i := range seq {
+		output(i)
+	}
+}
+
+// Matchers odwzorowuje nazwy algorytmów wyszukiwania wzorca na
+// funkcje, które przetwarzają wstępnie wzorzec
+var Matchers = map[string]func(pat []byte) Matcher{
+	"naive":         CompileNaive,
+	"backwardnaive": CompileBackwardNaive,
+	"bm":            CompileBoyerMoore,
+	"kmp":           CompileKMP,
+	"kmpdfa":        CompileKMPAutomaton,
+	"kr":            CompileKarpRabin,
+	"kr61":          CompileMersenneKarpRabin,
+	"shiftor":       CompileShiftOr,
+	"horspool":      CompileHorspool,
+	"sunday":        CompileSunday,
+	"twoway":        CompileTwoWay,
+	"bndm":          CompileBNDM,
+	"z":             CompileZMatch,
+}
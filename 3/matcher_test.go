@@ -0,0 +1,259 @@
+package matching
+
+import (
+	"bytes"
+	"fmt"
+	"iter"
+	"maps"
+	"math/rand"
+	"slices"
+	"testing"
+	"unicode/utf8"
+)
+
+// indexAll zwraca indeksy wszystkich, także nakładających się,
+// wystąpień wzorca `pat` w tekście `text`, wyszukane funkcją
+// bytes.Index
+func indexAll(pat, text []byte) []int {
+	r := []int{}
+	for i := 0; i <= len(text); i++ {
+		j := bytes.Index(text[i:], pat)
+		if j < 0 {
+			break
+		}
+		i += j
+		r = append(r, i)
+	}
+	return r
+}
+
+// findAll zwraca wycinek indeksów przekazanych przez obiekt `m`
+// funkcji `output`
+func findAll(m Matcher, text []byte) []int {
+	r := []int{}
+	m.FindAll(text, func(i int) { r = append(r, i) })
+	return r
+}
+
+// testMatchers sprawdza, czy wszystkie algorytmy wymienione w zmiennej
+// globalnej `Matchers` znajdują w tekstach `texts` te same wystąpienia
+// wzorca `pat`, co funkcja bytes.Index
+func testMatchers(t *testing.T, pat []byte, texts ...[]byte) {
+	t.Helper()
+	for _, name := range slices.Sorted(maps.Keys(Matchers)) {
+		// Jeden obiekt przeszukuje kolejno wszystkie teksty
+		m := Matchers[name](pat)
+		for _, text := range texts {
+			got := findAll(m, text)
+			want := indexAll(pat, text)
+			if !slices.Equal(got, want) {
+				t.Errorf("%s: FindAll(%#v, %#v) == %#v want %#v",
+					name, string(pat), string(text), got, want)
+			}
+			if got := slices.Collect(m.All(text)); !slices.Equal(got, want) {
+				t.Errorf("%s: All(%#v, %#v) == %#v want %#v",
+					name, string(pat), string(text), got, want)
+			}
+		}
+	}
+}
+
+func TestMatchers(t *testing.T) {
+	data := []struct {
+		pat   string
+		texts []string
+	}{
+		{"", []string{"", "a", "abc"}},
+		{"a", []string{"", "a", "b", "aaa", "bab"}},
+		{"aaa", []string{"", "aa", "aaaaa", "aabaaab"}},
+		{"abab", []string{"ab", "abababab", "abaabab", "babab"}},
+		{"abcabd", []string{"abcabcabd", "abcabdabcabd", "abcab"}},
+		{"pies", []string{"piespiespies", "pie", "kot i pies"}},
+		{"nienapełnienie", []string{
+			"nienapełnienienapełnienie", "nienapełnienie",
+			"nie napełnienie"}},
+		{"źdźbło", []string{"źdźbło źdźbła źdźbło", "dźbło"}},
+		{"\x00\xff", []string{"\x00\xff\x00\xff", "\xff\x00"}},
+	}
+	for _, d := range data {
+		texts := [][]byte{}
+		for _, text := range d.texts {
+			texts = append(texts, []byte(text))
+		}
+		testMatchers(t, []byte(d.pat), texts...)
+	}
+}
+
+// randomBytes zwraca losowy wycinek `n` bajtów z `k` początkowych
+// liter alfabetu łacińskiego
+func randomBytes(r *rand.Rand, n, k int) []byte {
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = byte('a' + r.Intn(k))
+	}
+	return b
+}
+
+// randomFullBytes zwraca losowy wycinek `n` bajtów z przedziału
+// od 0 do 255
+func randomFullBytes(r *rand.Rand, n int) []byte {
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = byte(r.Intn(256))
+	}
+	return b
+}
+
+// randomPolish zwraca losowy tekst w UTF-8, złożony z `n` liter
+// alfabetu polskiego i spacji
+func randomPolish(r *rand.Rand, n int) []byte {
+	letters := []rune("aąbcćdeęfghijklłmnńoóprsśtuwyzźż AĄĆĘŁŃÓŚŹŻ")
+	b := []byte{}
+	for range n {
+		b = utf8.AppendRune(b, letters[r.Intn(len(letters))])
+	}
+	return b
+}
+
+// plantedPatterns zwraca `count` wzorców wyciętych z tekstu `text`,
+// o długości od 1 do `maxLen` bajtów
+func plantedPatterns(r *rand.Rand, text []byte, count, maxLen int) [][]byte {
+	pats := [][]byte{}
+	for range count {
+		n := 1 + r.Intn(min(maxLen, len(text)))
+		i := r.Intn(len(text) - n + 1)
+		pats = append(pats, text[i:i+n])
+	}
+	return pats
+}
+
+func TestMatchersFullBytes(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for range 100 {
+		text := randomFullBytes(r, 1000)
+		for _, pat := range plantedPatterns(r, text, 3, 200) {
+			testMatchers(t, pat, text)
+		}
+	}
+}
+
+func TestMatchersPolish(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for range 100 {
+		text := randomPolish(r, 500)
+		for _, pat := range plantedPatterns(r, text, 3, 200) {
+			testMatchers(t, pat, text)
+		}
+	}
+}
+
+func TestMatchersRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for range 1000 {
+		k := 1 + r.Intn(4)
+		pat := randomBytes(r, r.Intn(10), k)
+		text := randomBytes(r, r.Intn(100), k)
+		testMatchers(t, pat, text)
+	}
+}
+
+func TestMatchersLong(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for _, n := range []int{63, 64, 65, 127, 128, 129, 200, 1000} {
+		for k := 1; k <= 2; k++ {
+			pat := randomBytes(r, n, k)
+			text := slices.Concat(randomBytes(r, 500, k), pat,
+				pat[:n/2], pat, randomBytes(r, 100, k), pat)
+			testMatchers(t, pat, text, pat, pat[1:])
+		}
+	}
+}
+
+func TestMatchersBreak(t *testing.T) {
+	pat := []byte("aa")
+	text := []byte("aaaaaaaa")
+	for _, name := range slices.Sorted(maps.Keys(Matchers)) {
+		got := []int{}
+		for i := range Matchers[name](pat).All(text) {
+			got = append(got, i)
+			if len(got) == 3 {
+				break
+			}
+		}
+		if want := []int{0, 1, 2}; !slices.Equal(got, want) {
+			t.Errorf("%s: first 3 elements of All(%#v, %#v) == %#v "+
+				"want %#v", name, string(pat), string(text), got, want)
+		}
+	}
+}
+
+func TestSeq(t *testing.T) {
+	pat := []byte("abab")
+	text := []byte("abababxabab")
+	want := []int{0, 2, 7}
+	seqs := map[string]func(pat, text []byte) iter.Seq[int]{
+		"NaiveSeq":             NaiveSeq,
+		"BackwardNaiveSeq":     BackwardNaiveSeq,
+		"BoyerMooreSeq":        BoyerMooreSeq,
+		"KMPSeq":               KMPSeq,
+		"KarpRabinSeq":         KarpRabinSeq,
+		"MersenneKarpRabinSeq": MersenneKarpRabinSeq,
+		"ShiftOrSeq":           ShiftOrSeq,
+		"HorspoolSeq":          HorspoolSeq,
+		"SundaySeq":            SundaySeq,
+		"TwoWaySeq":            TwoWaySeq,
+		"BNDMSeq":              BNDMSeq,
+	}
+	for name, seq := range seqs {
+		if got := slices.Collect(seq(pat, text)); !slices.Equal(got, want) {
+			t.Errorf("%s(%#v, %#v) == %#v want %#v",
+				name, string(pat), string(text), got, want)
+		}
+	}
+}
+
+func FuzzMatchers(f *testing.F) {
+	f.Add([]byte("aba"), []byte("abababa"))
+	f.Add([]byte(""), []byte("abc"))
+	f.Add([]byte("abc"), []byte("ab"))
+	f.Add([]byte("nienapełnienie"), []byte("nienapełnienienapełnienie"))
+	f.Fuzz(func(t *testing.T, pat, text []byte) {
+		testMatchers(t, pat, text)
+	})
+}
+
+// benchmarkMatchers mierzy czas wyszukiwania algorytmem `name` wzorców
+// o różnych długościach w losowym tekście o długości 1 MB nad
+// alfabetem złożonym z `k` liter
+func benchmarkMatchers(b *testing.B, name string, k int) {
+	r := rand.New(rand.NewSource(1))
+	text := randomBytes(r, 1<<20, k)
+	for _, n := range []int{2, 4, 8, 16, 32, 64, 65, 128, 256, 1024} {
+		// Wzorzec występuje w tekście co najmniej raz
+		i := r.Intn(len(text) - n)
+		pat := text[i : i+n]
+		b.Run(fmt.Sprintf("%s/len=%d", name, n), func(b *testing.B) {
+			m := Matchers[name](pat)
+			b.SetBytes(int64(len(text)))
+			for b.Loop() {
+				m.FindAll(text, func(int) {})
+			}
+		})
+	}
+}
+
+// BenchmarkMatchersDNA porównuje algorytmy dla alfabetu 4-literowego.
+// Przykład: go test -bench 'DNA/(shiftor|bm)/'
+func BenchmarkMatchersDNA(b *testing.B) {
+	for _, name := range slices.Sorted(maps.Keys(Matchers)) {
+		benchmarkMatchers(b, name, 4)
+	}
+}
+
+// BenchmarkMatchersLatin porównuje algorytmy dla alfabetu
+// 26-literowego
+func BenchmarkMatchersLatin(b *testing.B) {
+	for _, name := range slices.Sorted(maps.Keys(Matchers)) {
+		benchmarkMatchers(b, name, 26)
+	}
+}
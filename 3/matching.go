@@ -1,6 +1,7 @@
 package matching
 
 import (
+	"iter"
 	"slices"
 )
 
@@ -15,14 +16,41 @@ func hasPrefix(s, pat []byte) bool {
 	return true
 }
 
+// naiveMatcher implementuje algorytm naiwny
+type naiveMatcher struct {
+	pat []byte
+}
+
+// CompileNaive zwraca obiekt, który wyszukuje wzorzec `pat`
+// algorytmem naiwnym
+func CompileNaive(pat []byte) Matcher {
+	return naiveMatcher{pat}
+}
+
+func (m naiveMatcher) All(text []byte) iter.Seq[int] {
+	return func(yield func(int) bool) {
+		for i := 0; i+len(m.pat) <= len(text); i++ {
+			if hasPrefix(text[i:], m.pat) && !yield(i) {
+				return
+			}
+		}
+	}
+}
+
+func (m naiveMatcher) FindAll(text []byte, output func(int)) {
+	forEach(m.All(text), output)
+}
+
 // Naive wywołuje funkcję `output(i)` dla każdego takiego
 // indeksu `i`, że `slices.Equal(text[i:i+len(pat)], pat)`
 func Naive(pat, text []byte, output func(int)) {
-	for i := 0; i+len(pat) <= len(text); i++ {
-		if hasPrefix(text[i:], pat) {
-			output(i)
-		}
-	}
+	CompileNaive(pat).FindAll(text, output)
+}
+
+// NaiveSeq zwraca iterator po indeksach, które funkcja Naive
+// przekazuje funkcji `output`
+func NaiveSeq(pat, text []byte) iter.Seq[int] {
+	return CompileNaive(pat).All(text)
 }
 
 // backwardHasPrefix zwraca `true`, jeśli
@@ -36,14 +64,42 @@ func backwardHasPrefix(s, pat []byte) bool {
 	return true
 }
 
+// backwardNaiveMatcher implementuje algorytm naiwny, który
+// porównuje znaki od końca wzorca
+type backwardNaiveMatcher struct {
+	pat []byte
+}
+
+// CompileBackwardNaive zwraca obiekt, który wyszukuje wzorzec `pat`
+// algorytmem naiwnym, porównując znaki od końca wzorca
+func CompileBackwardNaive(pat []byte) Matcher {
+	return backwardNaiveMatcher{pat}
+}
+
+func (m backwardNaiveMatcher) All(text []byte) iter.Seq[int] {
+	return func(yield func(int) bool) {
+		for i := 0; i+len(m.pat) <= len(text); i++ {
+			if backwardHasPrefix(text[i:], m.pat) && !yield(i) {
+				return
+			}
+		}
+	}
+}
+
+func (m backwardNaiveMatcher) FindAll(text []byte, output func(int)) {
+	forEach(m.All(text), output)
+}
+
 // BackwardNaive wywołuje `output(i)` dla każdego takiego `i`,
 // że `slices.Equal(text[i:i+len(pat)], pat)`
 func BackwardNaive(pat, text []byte, output func(int)) {
-	for i := 0; i+len(pat) <= len(text); i++ {
-		if backwardHasPrefix(text[i:], pat) {
-			output(i)
-		}
-	}
+	CompileBackwardNaive(pat).FindAll(text, output)
+}
+
+// BackwardNaiveSeq zwraca iterator po indeksach, które funkcja BackwardNaive
+// przekazuje funkcji `output`
+func BackwardNaiveSeq(pat, text []byte) iter.Seq[int] {
+	return CompileBackwardNaive(pat).All(text)
 }
 
 // lenOfCommonPrefix zwraca długość najdłuższego
@@ -135,6 +191,43 @@ func simpleComputeGoodSuffixes(s []byte) []int {
 	return r
 }
 
+// computeGoodSuffixes zwraca w czasie liniowym ten sam wycinek, co
+// funkcja simpleComputeGoodSuffixes. Korzysta z wartości funkcji
+// Preprocess dla wzorca `s` i dla odwróconego wzorca `s`
+func computeGoodSuffixes(s []byte) []int {
+	n := len(s)
+	r := make([]int, n)
+	// zr[n-1-j] to długość najdłuższego wspólnego sufiksu
+	// łańcuchów `s[:j+1]` i `s`
+	zr := Preprocess(reversed(s))
+	z := Preprocess(s)
+	// Przesunięcie o `k > i` pozycji: `s[k:]` jest prefiksem `s`
+	shift := n
+	for i := n - 1; i >= 0; i-- {
+		if i+1 < n && z[i+1] == n-i-1 {
+			shift = i + 1
+		}
+		r[i] = shift
+	}
+	// Przesunięcie o `k <= i` pozycji: sufiks `s[i+1:]` występuje
+	// też w `s` tak, że kończy się na pozycji `j = n-1-k`, a przed
+	// nim stoi znak różny od `s[i]`. Dla rosnących `j` wygrywa
+	// najmniejsze przesunięcie
+	for j := 0; j < n-1; j++ {
+		if l := zr[n-1-j]; l <= j {
+			r[n-1-l] = n - 1 - j
+		}
+	}
+	return r
+}
+
+// reversed zwraca odwrócony łańcuch `s`
+func reversed(s []byte) []byte {
+	r := slices.Clone(s)
+	slices.Reverse(r)
+	return r
+}
+
 // boyerMooreHasPrefix zwraca parę (R, S). R ma wartość `true`,
 // jeśli `slices.Equal(text[:len(pat)], pat)`; S określa,
 // o ile pozycji w prawo należy przesunąć wzorzec `pat`
@@ -149,19 +242,53 @@ func boyerMooreHasPrefix(text, pat []byte,
 	return true, goodSuffixes[0]
 }
 
+// boyerMooreMatcher implementuje algorytm Boyera-Moore'a
+type boyerMooreMatcher struct {
+	pat             []byte
+	lastOccurrences map[byte]int
+	goodSuffixes    []int
+}
+
+// CompileBoyerMoore zwraca obiekt, który wyszukuje wzorzec `pat`
+// algorytmem Boyera-Moore'a
+func CompileBoyerMoore(pat []byte) Matcher {
+	if len(pat) == 0 {
+		return CompileNaive(pat)
+	}
+	return boyerMooreMatcher{
+		pat,
+		findLastOccurrences(pat),
+		computeGoodSuffixes(pat),
+	}
+}
+
+func (m boyerMooreMatcher) All(text []byte) iter.Seq[int] {
+	return func(yield func(int) bool) {
+		for i := 0; i+len(m.pat) <= len(text); /**/ {
+			found, shift := boyerMooreHasPrefix(text[i:], m.pat,
+				m.lastOccurrences, m.goodSuffixes)
+			if found && !yield(i) {
+				return
+			}
+			i += shift
+		}
+	}
+}
+
+func (m boyerMooreMatcher) FindAll(text []byte, output func(int)) {
+	forEach(m.All(text), output)
+}
+
 // BoyerMoore wywołuje `output(i)` dla każdego takiego `i`,
 // że `slices.Equal(text[i:i+len(pat)], pat)`
 func BoyerMoore(pat, text []byte, output func(int)) {
-	lastOccurrences := findLastOccurrences(pat)
-	goodSuffixes := simpleComputeGoodSuffixes(pat)
-	for i := 0; i+len(pat) <= len(text); /**/ {
-		found, shift := boyerMooreHasPrefix(text[i:], pat,
-			lastOccurrences, goodSuffixes)
-		if found {
-			output(i)
-		}
-		i += shift
-	}
+	CompileBoyerMoore(pat).FindAll(text, output)
+}
+
+// BoyerMooreSeq zwraca iterator po indeksach, które funkcja BoyerMoore
+// przekazuje funkcji `output`
+func BoyerMooreSeq(pat, text []byte) iter.Seq[int] {
+	return CompileBoyerMoore(pat).All(text)
 }
 
 // KMPPrefixFunction zwraca wycinek. `j`-ty element tego wycinka
@@ -177,22 +304,65 @@ func KMPPrefixFunction(s []byte) []int {
 	return p
 }
 
-func KMP(pat, text []byte, output func(int)) {
-	// len(pat) > 0
-	p := KMPPrefixFunction(pat)
-	j := 0
+// kmpMatcher implementuje algorytm Knutha-Morrisa-Pratta
+type kmpMatcher struct {
+	pat []byte
+	p   []int
+}
+
+// CompileKMP zwraca obiekt, który wyszukuje wzorzec `pat`
+// algorytmem Knutha-Morrisa-Pratta
+func CompileKMP(pat []byte) Matcher {
+	if len(pat) == 0 {
+		return CompileNaive(pat)
+	}
+	return kmpMatcher{pat, KMPPrefixFunction(pat)}
+}
+
+func (m kmpMatcher) All(text []byte) iter.Seq[int] {
+	return func(yield func(int) bool) {
+		m.findAllFrom(text, 0, yield)
+	}
+}
+
+func (m kmpMatcher) FindAll(text []byte, output func(int)) {
+	forEach(m.All(text), output)
+}
+
+// findAllFrom przetwarza tekst `text`, zaczynając od stanu `j`,
+// czyli długości dopasowanego już prefiksu wzorca, i zwraca stan po
+// przetworzeniu tego tekstu. Indeksy przekazywane funkcji `yield`
+// mogą być ujemne, jeśli wystąpienie wzorca zaczyna się przed `text`.
+// Jeśli `yield` zwróci `false`, findAllFrom kończy działanie
+func (m kmpMatcher) findAllFrom(text []byte, j int,
+	yield func(int) bool) int {
 	for i := 0; i < len(text); i++ {
-		for j > 0 && text[i] != pat[j] {
-			j = p[j]
+		for j > 0 && text[i] != m.pat[j] {
+			j = m.p[j]
 		}
-		if text[i] == pat[j] {
+		if text[i] == m.pat[j] {
 			j++
 		}
-		if j == len(pat) {
-			output(i - len(pat) + 1)
-			j = p[j]
+		if j == len(m.pat) {
+			if !yield(i - len(m.pat) + 1) {
+				return j
+			}
+			j = m.p[j]
 		}
 	}
+	return j
+}
+
+// KMP wywołuje `output(i)` dla każdego takiego `i`,
+// że `slices.Equal(text[i:i+len(pat)], pat)`
+func KMP(pat, text []byte, output func(int)) {
+	CompileKMP(pat).FindAll(text, output)
+}
+
+// KMPSeq zwraca iterator po indeksach, które funkcja KMP
+// przekazuje funkcji `output`
+func KMPSeq(pat, text []byte) iter.Seq[int] {
+	return CompileKMP(pat).All(text)
 }
 
 // hashByteModN zwraca liczbę całkowitą z przedziału [0, n)
@@ -235,43 +405,74 @@ func twoToPower8PModN(p int, n uint64) uint64 {
 
 // unhashByteModN zwraca liczbę całkowitą z przedziału [0, n)
 func unhashByteModN(b byte, h, n, power uint64) uint64 {
-	// Nie ma przepełnienia, jeśli
-	// h - power*uint64(b) > -(1<<64)
-	// 0 - power*(1<<8-1) > -(1<<64)
+	// Nie ma przepełnienia przy mnożeniu, jeśli
 	// power*(1<<8-1) < 1<<64
-	// power<<8 - power < 1<<64
 	// power<<8 < 1<<64
-	// power<<8>>8 < 1<<64>>8
 	// power < 1<<56
-	r := h - power*uint64(b)
-	if int64(r) < 0 {
-		r += n * (-r/n + 1)
-	}
-	return r
+	// Odjemnik power*uint64(b)%n jest mniejszy niż n, więc
+	// 0 < h + n - power*uint64(b)%n < 2*n < 1<<64
+	return (h + n - power*uint64(b)%n) % n
 }
 
 // Największa liczba pierwsza mniejsza niż 1<<56
 // https://t5k.org/lists/2small/0bit.html
 const N uint64 = 1<<56 - 5
 
-func KarpRabin(pat, text []byte, output func(int)) {
-	// len(pat) <= len(text)
-	h := hashBytesModN(text[:len(pat)], N)
-	ph := hashBytesModN(pat, N)
-	power := twoToPower8PModN(len(pat), N)
-	for i := 0; ; i++ {
-		// h == hashBytesModN(text[i:i+len(pat)], N)
-		if h == ph && slices.Equal(pat, text[i:i+len(pat)]) {
-			output(i)
+// karpRabinMatcher implementuje algorytm Karpa-Rabina
+type karpRabinMatcher struct {
+	pat   []byte
+	ph    uint64
+	power uint64
+}
+
+// CompileKarpRabin zwraca obiekt, który wyszukuje wzorzec `pat`
+// algorytmem Karpa-Rabina
+func CompileKarpRabin(pat []byte) Matcher {
+	return karpRabinMatcher{
+		pat,
+		hashBytesModN(pat, N),
+		twoToPower8PModN(len(pat), N),
+	}
+}
+
+func (m karpRabinMatcher) All(text []byte) iter.Seq[int] {
+	return func(yield func(int) bool) {
+		pat := m.pat
+		if len(pat) > len(text) {
+			return
 		}
-		if i+len(pat) >= len(text) {
-			break
+		h := hashBytesModN(text[:len(pat)], N)
+		for i := 0; ; i++ {
+			// h == hashBytesModN(text[i:i+len(pat)], N)
+			if h == m.ph && slices.Equal(pat, text[i:i+len(pat)]) &&
+				!yield(i) {
+				return
+			}
+			if i+len(pat) >= len(text) {
+				break
+			}
+			h = hashByteModN(text[i+len(pat)], h, N)
+			h = unhashByteModN(text[i], h, N, m.power)
 		}
-		h = hashByteModN(text[i+len(pat)], h, N)
-		h = unhashByteModN(text[i], h, N, power)
 	}
 }
 
+func (m karpRabinMatcher) FindAll(text []byte, output func(int)) {
+	forEach(m.All(text), output)
+}
+
+// KarpRabin wywołuje `output(i)` dla każdego takiego `i`,
+// że `slices.Equal(text[i:i+len(pat)], pat)`
+func KarpRabin(pat, text []byte, output func(int)) {
+	CompileKarpRabin(pat).FindAll(text, output)
+}
+
+// KarpRabinSeq zwraca iterator po indeksach, które funkcja KarpRabin
+// przekazuje funkcji `output`
+func KarpRabinSeq(pat, text []byte) iter.Seq[int] {
+	return CompileKarpRabin(pat).All(text)
+}
+
 // setNthBit zwraca maskę, w której bit na pozycji n jest równy 1
 func setNthBit(n int) uint64 {
 	return uint64(1) << n
@@ -297,17 +498,418 @@ func makeMask(pat []byte) [256]uint64 {
 	return m
 }
 
-func ShiftOr(pat, text []byte, output func(int)) {
-	// len(pat) != 0
-	m := makeMask(pat)
-	s := ^uint64(0) // Ustaw wszystkie bity maski s
+// shiftOrMatcher implementuje algorytm Shift-Or
+type shiftOrMatcher struct {
+	m [256]uint64
+	n int
+}
+
+// CompileShiftOr zwraca obiekt, który wyszukuje wzorzec `pat`
+// algorytmem Shift-Or. Wzorce o długości co najwyżej 64 znaków
+// reprezentuje jedna liczba typu uint64, dłuższe wzorce - wektory
+// bitowe złożone z wielu liczb
+func CompileShiftOr(pat []byte) Matcher {
+	switch {
+	case len(pat) == 0:
+		return CompileNaive(pat)
+	case len(pat) <= 64:
+		return shiftOrMatcher{makeMask(pat), len(pat)}
+	default:
+		return compileLongShiftOr(pat)
+	}
+}
+
+func (m shiftOrMatcher) All(text []byte) iter.Seq[int] {
+	return func(yield func(int) bool) {
+		// Ustaw wszystkie bity maski s
+		m.findAllFrom(text, ^uint64(0), yield)
+	}
+}
+
+func (m shiftOrMatcher) FindAll(text []byte, output func(int)) {
+	forEach(m.All(text), output)
+}
+
+// findAllFrom przetwarza tekst `text`, zaczynając od maski `s`,
+// i zwraca maskę po przetworzeniu tego tekstu. Indeksy przekazywane
+// funkcji `yield` mogą być ujemne, jeśli wystąpienie wzorca zaczyna
+// się przed `text`. Jeśli `yield` zwróci `false`, findAllFrom kończy
+// działanie
+func (m shiftOrMatcher) findAllFrom(text []byte, s uint64,
+	yield func(int) bool) uint64 {
 	for i, c := range text {
-		// Dla 1 < j < min(len(pat), i) zachodzi
+		// Dla 1 < j < min(m.n, i) zachodzi
 		// (nthBit(s, j-1) == 0) ==
 		//    slices.Equal(pat[:j], text[i-j:i])
-		s = (s << 1) | m[c] // Shift-Or
-		if nthBit(s, len(pat)-1) == 0 {
-			output(i - len(pat) + 1)
+		s = (s << 1) | m.m[c] // Shift-Or
+		if nthBit(s, m.n-1) == 0 && !yield(i-m.n+1) {
+			return s
 		}
 	}
+	return s
+}
+
+// longShiftOrMatcher implementuje algorytm Shift-Or dla wzorców
+// dłuższych niż 64 znaki. Maska znaku `c` to wycinek
+// `m[c*words:(c+1)*words]`; `j`-ty bit maski to bit `j%64`
+// jej `j/64`-tej liczby
+type longShiftOrMatcher struct {
+	m     []uint64
+	n     int
+	words int
+}
+
+// compileLongShiftOr zwraca obiekt, który wyszukuje wzorzec `pat`
+// algorytmem Shift-Or z wektorami bitowymi o długości `len(pat)`
+func compileLongShiftOr(pat []byte) longShiftOrMatcher {
+	words := (len(pat) + 63) / 64
+	m := make([]uint64, 256*words)
+	for i := range m {
+		m[i] = ^uint64(0)
+	}
+	for j, c := range pat {
+		m[int(c)*words+j/64] &^= setNthBit(j % 64)
+	}
+	return longShiftOrMatcher{m, len(pat), words}
+}
+
+// newState zwraca wektor bitowy, którego wszystkie bity są ustawione
+func (m longShiftOrMatcher) newState() []uint64 {
+	s := make([]uint64, m.words)
+	for w := range s {
+		s[w] = ^uint64(0)
+	}
+	return s
+}
+
+func (m longShiftOrMatcher) All(text []byte) iter.Seq[int] {
+	return func(yield func(int) bool) {
+		m.findAllFrom(text, m.newState(), yield)
+	}
+}
+
+func (m longShiftOrMatcher) FindAll(text []byte, output func(int)) {
+	forEach(m.All(text), output)
+}
+
+// findAllFrom działa tak jak shiftOrMatcher.findAllFrom, ale zmienia
+// wektor bitowy `s` w miejscu
+func (m longShiftOrMatcher) findAllFrom(text []byte, s []uint64,
+	yield func(int) bool) {
+	last := m.words - 1
+	for i, c := range text {
+		mc := m.m[int(c)*m.words : (int(c)+1)*m.words]
+		// Przesuń cały wektor s o jeden bit w stronę starszych bitów
+		for w := last; w > 0; w-- {
+			s[w] = (s[w]<<1 | s[w-1]>>63) | mc[w]
+		}
+		s[0] = s[0]<<1 | mc[0]
+		if nthBit(s[last], (m.n-1)%64) == 0 && !yield(i-m.n+1) {
+			return
+		}
+	}
+}
+
+// ShiftOr wywołuje `output(i)` dla każdego takiego `i`,
+// że `slices.Equal(text[i:i+len(pat)], pat)`
+func ShiftOr(pat, text []byte, output func(int)) {
+	CompileShiftOr(pat).FindAll(text, output)
+}
+
+// ShiftOrSeq zwraca iterator po indeksach, które funkcja ShiftOr
+// przekazuje funkcji `output`
+func ShiftOrSeq(pat, text []byte) iter.Seq[int] {
+	return CompileShiftOr(pat).All(text)
+}
+
+// horspoolMatcher implementuje algorytm Boyera-Moore'a-Horspoola
+type horspoolMatcher struct {
+	pat []byte
+	// shift[c] to przesunięcie wzorca, jeśli ostatni znak okna
+	// tekstu jest równy c
+	shift [256]int
+}
+
+// CompileHorspool zwraca obiekt, który wyszukuje wzorzec `pat`
+// algorytmem Boyera-Moore'a-Horspoola
+func CompileHorspool(pat []byte) Matcher {
+	if len(pat) == 0 {
+		return CompileNaive(pat)
+	}
+	m := &horspoolMatcher{pat: pat}
+	for c := range m.shift {
+		m.shift[c] = len(pat)
+	}
+	for j, c := range pat[:len(pat)-1] {
+		m.shift[c] = len(pat) - 1 - j
+	}
+	return m
+}
+
+func (m *horspoolMatcher) All(text []byte) iter.Seq[int] {
+	return func(yield func(int) bool) {
+		n := len(m.pat)
+		for i := 0; i+n <= len(text); i += m.shift[text[i+n-1]] {
+			if backwardHasPrefix(text[i:], m.pat) && !yield(i) {
+				return
+			}
+		}
+	}
+}
+
+func (m *horspoolMatcher) FindAll(text []byte, output func(int)) {
+	forEach(m.All(text), output)
+}
+
+// Horspool wywołuje `output(i)` dla każdego takiego `i`,
+// że `slices.Equal(text[i:i+len(pat)], pat)`
+func Horspool(pat, text []byte, output func(int)) {
+	CompileHorspool(pat).FindAll(text, output)
+}
+
+// HorspoolSeq zwraca iterator po indeksach, które funkcja Horspool
+// przekazuje funkcji `output`
+func HorspoolSeq(pat, text []byte) iter.Seq[int] {
+	return CompileHorspool(pat).All(text)
+}
+
+// sundayMatcher implementuje algorytm Sundaya (Quick Search)
+type sundayMatcher struct {
+	pat []byte
+	// shift[c] to przesunięcie wzorca, jeśli znak tuż za oknem
+	// tekstu jest równy c
+	shift [256]int
+}
+
+// CompileSunday zwraca obiekt, który wyszukuje wzorzec `pat`
+// algorytmem Sundaya
+func CompileSunday(pat []byte) Matcher {
+	if len(pat) == 0 {
+		return CompileNaive(pat)
+	}
+	m := &sundayMatcher{pat: pat}
+	for c := range m.shift {
+		m.shift[c] = len(pat) + 1
+	}
+	for j, c := range pat {
+		m.shift[c] = len(pat) - j
+	}
+	return m
+}
+
+func (m *sundayMatcher) All(text []byte) iter.Seq[int] {
+	return func(yield func(int) bool) {
+		n := len(m.pat)
+		for i := 0; i+n <= len(text); /**/ {
+			if hasPrefix(text[i:], m.pat) && !yield(i) {
+				return
+			}
+			if i+n == len(text) {
+				break
+			}
+			i += m.shift[text[i+n]]
+		}
+	}
+}
+
+func (m *sundayMatcher) FindAll(text []byte, output func(int)) {
+	forEach(m.All(text), output)
+}
+
+// Sunday wywołuje `output(i)` dla każdego takiego `i`,
+// że `slices.Equal(text[i:i+len(pat)], pat)`
+func Sunday(pat, text []byte, output func(int)) {
+	CompileSunday(pat).FindAll(text, output)
+}
+
+// SundaySeq zwraca iterator po indeksach, które funkcja Sunday
+// przekazuje funkcji `output`
+func SundaySeq(pat, text []byte) iter.Seq[int] {
+	return CompileSunday(pat).All(text)
+}
+
+// maximalSuffix zwraca parę (L, P). `s[L+1:]` to maksymalny
+// leksykograficznie sufiks łańcucha `s` w porządku bajtów, jeśli
+// `reverse` ma wartość `false`, albo w porządku odwrotnym, jeśli
+// `reverse` ma wartość `true`. P to okres tego sufiksu
+func maximalSuffix(s []byte, reverse bool) (int, int) {
+	ms := -1
+	j := 0
+	k := 1
+	p := 1
+	for j+k < len(s) {
+		a := s[j+k]
+		b := s[ms+k]
+		if reverse {
+			a, b = b, a
+		}
+		switch {
+		case a < b:
+			j += k
+			k = 1
+			p = j - ms
+		case a == b:
+			if k != p {
+				k++
+			} else {
+				j += p
+				k = 1
+			}
+		default:
+			ms = j
+			j = ms + 1
+			k = 1
+			p = 1
+		}
+	}
+	return ms, p
+}
+
+// twoWayMatcher implementuje algorytm Two-Way Crochemore'a i Perrina
+type twoWayMatcher struct {
+	pat []byte
+	// `pat[:ell+1]` i `pat[ell+1:]` to faktoryzacja krytyczna wzorca
+	ell int
+	per int
+	// `true`, jeśli `per` to okres całego wzorca
+	periodic bool
+}
+
+// CompileTwoWay zwraca obiekt, który wyszukuje wzorzec `pat`
+// algorytmem Two-Way. Ten algorytm działa w czasie liniowym
+// i korzysta ze stałej ilości dodatkowej pamięci
+func CompileTwoWay(pat []byte) Matcher {
+	if len(pat) == 0 {
+		return CompileNaive(pat)
+	}
+	i, p := maximalSuffix(pat, false)
+	j, q := maximalSuffix(pat, true)
+	ell, per := j, q
+	if i > j {
+		ell, per = i, p
+	}
+	if slices.Equal(pat[:ell+1], pat[per:per+ell+1]) {
+		return twoWayMatcher{pat, ell, per, true}
+	}
+	per = max(ell+1, len(pat)-ell-1) + 1
+	return twoWayMatcher{pat, ell, per, false}
+}
+
+func (m twoWayMatcher) All(text []byte) iter.Seq[int] {
+	return func(yield func(int) bool) {
+		pat := m.pat
+		n := len(pat)
+		// Długość prefiksu wzorca, który na pewno pasuje do tekstu
+		// po przesunięciu o okres, pomniejszona o 1
+		memory := -1
+		for j := 0; j+n <= len(text); /**/ {
+			// Porównuj prawą część wzorca od lewej do prawej
+			i := max(m.ell, memory) + 1
+			for i < n && pat[i] == text[i+j] {
+				i++
+			}
+			if i < n {
+				j += i - m.ell
+				memory = -1
+				continue
+			}
+			// Porównuj lewą część wzorca od prawej do lewej
+			i = m.ell
+			for i > memory && pat[i] == text[i+j] {
+				i--
+			}
+			if i <= memory && !yield(j) {
+				return
+			}
+			j += m.per
+			if m.periodic {
+				memory = n - m.per - 1
+			}
+		}
+	}
+}
+
+func (m twoWayMatcher) FindAll(text []byte, output func(int)) {
+	forEach(m.All(text), output)
+}
+
+// TwoWay wywołuje `output(i)` dla każdego takiego `i`,
+// że `slices.Equal(text[i:i+len(pat)], pat)`
+func TwoWay(pat, text []byte, output func(int)) {
+	CompileTwoWay(pat).FindAll(text, output)
+}
+
+// TwoWaySeq zwraca iterator po indeksach, które funkcja TwoWay
+// przekazuje funkcji `output`
+func TwoWaySeq(pat, text []byte) iter.Seq[int] {
+	return CompileTwoWay(pat).All(text)
+}
+
+// bndmMatcher implementuje algorytm BNDM (Backward Nondeterministic
+// DAWG Matching). Wzorce dłuższe niż 64 znaki wyszukuje, znajdując
+// ich 64-znakowe prefiksy i porównując resztę wzorca z tekstem
+type bndmMatcher struct {
+	pat []byte
+	// Długość prefiksu wzorca, który reprezentują maski b
+	n int
+	// Bit `n-1-j` maski b[c] jest równy 1, jeśli `pat[j] == c`
+	b [256]uint64
+}
+
+// CompileBNDM zwraca obiekt, który wyszukuje wzorzec `pat`
+// algorytmem BNDM
+func CompileBNDM(pat []byte) Matcher {
+	if len(pat) == 0 {
+		return CompileNaive(pat)
+	}
+	m := &bndmMatcher{pat: pat, n: min(len(pat), 64)}
+	for j, c := range pat[:m.n] {
+		m.b[c] |= setNthBit(m.n - 1 - j)
+	}
+	return m
+}
+
+func (m *bndmMatcher) All(text []byte) iter.Seq[int] {
+	return func(yield func(int) bool) {
+		for pos := 0; pos+len(m.pat) <= len(text); /**/ {
+			// Bit k maski d jest równy 1, jeśli wczytany dotąd
+			// sufiks okna tekstu jest czynnikiem prefiksu wzorca
+			// kończącym się na pozycji `m.n-1-k`
+			d := ^uint64(0)
+			j := m.n
+			last := m.n
+			for j > 0 && d != 0 {
+				d &= m.b[text[pos+j-1]]
+				j--
+				if nthBit(d, m.n-1) != 0 {
+					if j > 0 {
+						// Sufiks okna jest prefiksem wzorca
+						last = j
+					} else if hasPrefix(text[pos+m.n:], m.pat[m.n:]) {
+						if !yield(pos) {
+							return
+						}
+					}
+				}
+				d <<= 1
+			}
+			pos += last
+		}
+	}
+}
+
+func (m *bndmMatcher) FindAll(text []byte, output func(int)) {
+	forEach(m.All(text), output)
+}
+
+// BNDM wywołuje `output(i)` dla każdego takiego `i`,
+// że `slices.Equal(text[i:i+len(pat)], pat)`
+func BNDM(pat, text []byte, output func(int)) {
+	CompileBNDM(pat).FindAll(text, output)
+}
+
+// BNDMSeq zwraca iterator po indeksach, które funkcja BNDM
+// przekazuje funkcji `output`
+func BNDMSeq(pat, text []byte) iter.Seq[int] {
+	return CompileBNDM(pat).All(text)
 }
@@ -1,6 +1,7 @@
 package matching
 
 import (
+	"math/rand"
 	"slices"
 	"testing"
 )
@@ -24,6 +25,39 @@ func TestPreprocess(t *testing.T) {
 	}
 }
 
+func TestComputeGoodSuffixes(t *testing.T) {
+	// Wszystkie wzorce nad alfabetem {a, b} o długości co najwyżej 12
+	for n := 1; n <= 12; n++ {
+		for bits := 0; bits < 1<<n; bits++ {
+			s := make([]byte, n)
+			for i := range s {
+				s[i] = 'a' + byte(bits>>i&1)
+			}
+			got := computeGoodSuffixes(s)
+			want := simpleComputeGoodSuffixes(s)
+			if !slices.Equal(got, want) {
+				t.Fatalf(`computeGoodSuffixes(%#v) == %#v want %#v`,
+					string(s), got, want)
+			}
+		}
+	}
+}
+
+// goodSuffixesPattern to losowy wzorzec o długości 10 000 bajtów
+var goodSuffixesPattern = randomBytes(rand.New(rand.NewSource(1)), 10000, 4)
+
+func BenchmarkComputeGoodSuffixes(b *testing.B) {
+	for b.Loop() {
+		computeGoodSuffixes(goodSuffixesPattern)
+	}
+}
+
+func BenchmarkSimpleComputeGoodSuffixes(b *testing.B) {
+	for b.Loop() {
+		simpleComputeGoodSuffixes(goodSuffixesPattern)
+	}
+}
+
 func indices(pat, text []byte) []int {
 	r := []int{}
 	for i := 0; i+len(pat) <= len(text); i++ {
@@ -0,0 +1,143 @@
+package matching
+
+import (
+	"iter"
+	"math/bits"
+	"math/rand/v2"
+	"slices"
+)
+
+// Liczba pierwsza Mersenne'a 2**61-1
+const M61 uint64 = 1<<61 - 1
+
+// reduceM61 zwraca a % M61 dla a < 1<<64
+func reduceM61(a uint64) uint64 {
+	r := a>>61 + a&M61
+	if r >= M61 {
+		r -= M61
+	}
+	return r
+}
+
+// mulModM61 zwraca a*b % M61 dla a, b < M61
+func mulModM61(a, b uint64) uint64 {
+	return reduceM61(lazyMulModM61(a, b))
+}
+
+// lazyMulModM61 zwraca liczbę mniejszą niż 1<<63 i przystającą do a*b
+// modulo M61 dla a < 1<<62, b < M61
+func lazyMulModM61(a, b uint64) uint64 {
+	// a*b < 1<<123, więc hi < 1<<59
+	hi, lo := bits.Mul64(a, b)
+	// a*b == (hi<<3 | lo>>61) << 61 + lo&M61, a 1<<61 % M61 == 1
+	return (hi<<3 | lo>>61) + lo&M61
+}
+
+// addModM61 zwraca (a+b) % M61 dla a, b < M61
+func addModM61(a, b uint64) uint64 {
+	r := a + b
+	if r >= M61 {
+		r -= M61
+	}
+	return r
+}
+
+// hashBytesModM61 zwraca wartość wielomianu o współczynnikach `bs`
+// w punkcie `base` modulo M61
+func hashBytesModM61(bs []byte, base uint64) uint64 {
+	h := uint64(0)
+	for _, b := range bs {
+		h = addModM61(mulModM61(h, base), uint64(b))
+	}
+	return h
+}
+
+// powModM61 zwraca base**p % M61
+func powModM61(base uint64, p int) uint64 {
+	r := uint64(1)
+	for ; p > 0; p >>= 1 {
+		if p&1 != 0 {
+			r = mulModM61(r, base)
+		}
+		base = mulModM61(base, base)
+	}
+	return r
+}
+
+// mersenneKarpRabinMatcher implementuje algorytm Karpa-Rabina
+// z funkcją haszującą modulo M61
+type mersenneKarpRabinMatcher struct {
+	pat  []byte
+	base uint64
+	ph   uint64
+	// unhash[c] == c * base**len(pat) % M61
+	unhash [256]uint64
+}
+
+// CompileMersenneKarpRabin zwraca obiekt, który wyszukuje wzorzec
+// `pat` algorytmem Karpa-Rabina. W przeciwieństwie do funkcji
+// CompileKarpRabin oblicza wartości funkcji haszującej modulo M61
+// bez dzielenia, a podstawę tej funkcji losuje, więc nie da się
+// z góry przygotować tekstu, w którym wiele okien ma taką samą
+// wartość funkcji haszującej, jak wzorzec
+func CompileMersenneKarpRabin(pat []byte) Matcher {
+	return compileMersenneKarpRabin(pat, 256+rand.Uint64N(M61-256))
+}
+
+// compileMersenneKarpRabin działa tak jak CompileMersenneKarpRabin,
+// ale korzysta z podanej podstawy `base`
+func compileMersenneKarpRabin(pat []byte, base uint64) Matcher {
+	m := &mersenneKarpRabinMatcher{
+		pat:  pat,
+		base: base,
+		ph:   hashBytesModM61(pat, base),
+	}
+	power := powModM61(base, len(pat))
+	for c := range m.unhash {
+		m.unhash[c] = mulModM61(uint64(c), power)
+	}
+	return m
+}
+
+func (m *mersenneKarpRabinMatcher) All(text []byte) iter.Seq[int] {
+	return func(yield func(int) bool) {
+		pat := m.pat
+		if len(pat) > len(text) {
+			return
+		}
+		h := hashBytesModM61(text[:len(pat)], m.base)
+		for i := 0; ; i++ {
+			// h < M61+8 i h % M61 == hashBytesModM61(text[i:i+len(pat)],
+			// m.base)
+			if (h == m.ph || h == m.ph+M61) &&
+				slices.Equal(pat, text[i:i+len(pat)]) && !yield(i) {
+				return
+			}
+			if i+len(pat) >= len(text) {
+				break
+			}
+			// Nie ma przepełnienia, bo suma jest mniejsza niż
+			// 1<<63 + 1<<8 + 2*M61 < 1<<64. Redukcja bez porównania
+			// daje liczbę mniejszą niż M61+8
+			h = lazyMulModM61(h, m.base) +
+				uint64(text[i+len(pat)]) + 2*M61 - m.unhash[text[i]]
+			h = h>>61 + h&M61
+		}
+	}
+}
+
+func (m *mersenneKarpRabinMatcher) FindAll(text []byte, output func(int)) {
+	forEach(m.All(text), output)
+}
+
+// MersenneKarpRabin wywołuje `output(i)` dla każdego takiego `i`,
+// że `slices.Equal(text[i:i+len(pat)], pat)`
+func MersenneKarpRabin(pat, text []byte, output func(int)) {
+	CompileMersenneKarpRabin(pat).FindAll(text, output)
+}
+
+// MersenneKarpRabinSeq zwraca iterator po indeksach, które funkcja
+// MersenneKarpRabin przekazuje funkcji `output`
+func MersenneKarpRabinSeq(pat, text []byte) iter.Seq[int] {
+	return CompileMersenneKarpRabin(pat).All(text)
+}
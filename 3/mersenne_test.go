@@ -0,0 +1,112 @@
+package matching
+
+import (
+	"encoding/binary"
+	"math/big"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestMulModM61(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	m := new(big.Int).SetUint64(M61)
+	data := [][2]uint64{{0, 0}, {1, M61 - 1}, {M61 - 1, M61 - 1}}
+	for range 1000 {
+		data = append(data, [2]uint64{
+			r.Uint64() % M61, r.Uint64() % M61})
+	}
+	for _, d := range data {
+		want := new(big.Int).SetUint64(d[0])
+		want.Mul(want, new(big.Int).SetUint64(d[1])).Mod(want, m)
+		if got := mulModM61(d[0], d[1]); got != want.Uint64() {
+			t.Errorf("mulModM61(%d, %d) == %d want %d",
+				d[0], d[1], got, want.Uint64())
+		}
+	}
+}
+
+// collidingTexts zwraca wzorzec o długości 8 bajtów i tekst, w którym
+// ten wzorzec występuje tylko na pozycji 8, a na pozycjach 0 i 16
+// występuje inny łańcuch, który jako liczba zapisana w systemie
+// o podstawie 256 jest większy od wzorca o `n`
+func collidingTexts(n uint64) ([]byte, []byte) {
+	x := uint64(0x0102030405060708)
+	pat := binary.BigEndian.AppendUint64(nil, x)
+	other := binary.BigEndian.AppendUint64(nil, x+n)
+	return pat, slices.Concat(other, pat, other)
+}
+
+func TestKarpRabinCollisions(t *testing.T) {
+	want := []int{8}
+	// Funkcja hashBytesModN ma taką samą wartość dla obu łańcuchów
+	pat, text := collidingTexts(N)
+	if hashBytesModN(pat, N) != hashBytesModN(text[:8], N) {
+		t.Fatalf("hashBytesModN(%#v) != hashBytesModN(%#v)", pat, text[:8])
+	}
+	if got := findAll(CompileKarpRabin(pat), text); !slices.Equal(got, want) {
+		t.Errorf("KarpRabin(%#v, %#v) == %#v want %#v", pat, text, got, want)
+	}
+	// Z podstawą 256 funkcja hashBytesModM61 ma taką samą wartość dla
+	// obu łańcuchów
+	pat, text = collidingTexts(M61)
+	if hashBytesModM61(pat, 256) != hashBytesModM61(text[:8], 256) {
+		t.Fatalf("hashBytesModM61(%#v) != hashBytesModM61(%#v)",
+			pat, text[:8])
+	}
+	m := compileMersenneKarpRabin(pat, 256)
+	if got := findAll(m, text); !slices.Equal(got, want) {
+		t.Errorf("MersenneKarpRabin(%#v, %#v) with base 256 == %#v want %#v",
+			pat, text, got, want)
+	}
+	// Z losową podstawą wartości funkcji hashBytesModM61 dla obu
+	// łańcuchów są różne
+	for range 100 {
+		base := 256 + rand.Uint64()%(M61-256)
+		if hashBytesModM61(pat, base) == hashBytesModM61(text[:8], base) {
+			t.Errorf("hashBytesModM61(%#v) == hashBytesModM61(%#v) "+
+				"with base %d", pat, text[:8], base)
+		}
+	}
+}
+
+// hashText to losowy tekst o długości 1 MB
+var hashText = randomBytes(rand.New(rand.NewSource(1)), 1<<20, 26)
+
+// Długość okna, dla którego funkcje BenchmarkRollingHash* obliczają
+// wartość funkcji haszującej
+const hashWindow = 16
+
+func BenchmarkRollingHashModN(b *testing.B) {
+	power := twoToPower8PModN(hashWindow, N)
+	b.SetBytes(int64(len(hashText)))
+	for b.Loop() {
+		h := uint64(0)
+		for i, c := range hashText {
+			h = hashByteModN(c, h, N)
+			if i >= hashWindow {
+				h = unhashByteModN(hashText[i-hashWindow], h, N, power)
+			}
+		}
+	}
+}
+
+func BenchmarkRollingHashModM61(b *testing.B) {
+	base := 256 + rand.Uint64()%(M61-256)
+	power := powModM61(base, hashWindow)
+	unhash := [256]uint64{}
+	for c := range unhash {
+		unhash[c] = mulModM61(uint64(c), power)
+	}
+	b.SetBytes(int64(len(hashText)))
+	for b.Loop() {
+		h := uint64(0)
+		for i, c := range hashText {
+			h = lazyMulModM61(h, base) + uint64(c) + 2*M61
+			if i >= hashWindow {
+				h -= unhash[hashText[i-hashWindow]]
+			}
+			h = h>>61 + h&M61
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package matching
+
+import (
+	"iter"
+	"math/bits"
+	"slices"
+)
+
+// karpRabinBucket to grupa wzorców o podobnej długości: od 2**(k-1)
+// do 2**k-1 bajtów dla pewnego k
+type karpRabinBucket struct {
+	// Długość najkrótszego wzorca z grupy
+	n     int
+	power uint64
+	// Odwzorowuje wartości funkcji hashBytesModN prefiksów wzorców
+	// o długości `n` na indeksy tych wzorców
+	patterns map[uint64][]int
+}
+
+// MultiKarpRabinMatcher wyszukuje jednocześnie wiele wzorców
+// algorytmem Karpa-Rabina
+type MultiKarpRabinMatcher struct {
+	pats    [][]byte
+	buckets []*karpRabinBucket
+}
+
+// CompileMultiKarpRabin zwraca obiekt, który wyszukuje jednocześnie
+// wszystkie wzorce `pats`. Wzorce o podobnej długości trafiają do
+// jednej grupy, w której szuka się ich prefiksów o jednakowej długości
+// za pomocą jednej wartości funkcji haszującej okna tekstu
+func CompileMultiKarpRabin(pats [][]byte) *MultiKarpRabinMatcher {
+	m := &MultiKarpRabinMatcher{pats: pats}
+	byLen := map[int]*karpRabinBucket{}
+	for _, pat := range pats {
+		b, ok := byLen[bits.Len(uint(len(pat)))]
+		if !ok {
+			b = &karpRabinBucket{n: len(pat)}
+			byLen[bits.Len(uint(len(pat)))] = b
+			m.buckets = append(m.buckets, b)
+		}
+		b.n = min(b.n, len(pat))
+	}
+	for _, b := range m.buckets {
+		b.power = twoToPower8PModN(b.n, N)
+		b.patterns = map[uint64][]int{}
+	}
+	for k, pat := range pats {
+		b := byLen[bits.Len(uint(len(pat)))]
+		ph := hashBytesModN(pat[:b.n], N)
+		b.patterns[ph] = append(b.patterns[ph], k)
+	}
+	slices.SortFunc(m.buckets, func(a, b *karpRabinBucket) int {
+		return b.n - a.n
+	})
+	return m
+}
+
+// report wywołuje `yield(k, i)` dla każdego wzorca `pats[k]` z grupy
+// `b`, którego prefiks ma wartość funkcji haszującej `h` i który
+// występuje w tekście `text` na pozycji `i`. Zwraca `false`, jeśli
+// `yield` zwróciła `false`
+func (m *MultiKarpRabinMatcher) report(b *karpRabinBucket, h uint64,
+	text []byte, i int, yield func(int, int) bool) bool {
+	for _, k := range b.patterns[h] {
+		if i+len(m.pats[k]) <= len(text) &&
+			hasPrefix(text[i:], m.pats[k]) && !yield(k, i) {
+			return false
+		}
+	}
+	return true
+}
+
+// All zwraca iterator po takich parach indeksu wzorca `k` i indeksu
+// tekstu `i`, że `slices.Equal(text[i:i+len(pats[k])], pats[k])`.
+// Przechodzi przez tekst jeden raz, więc kolejność wystąpień zależy
+// od długości wzorców
+func (m *MultiKarpRabinMatcher) All(text []byte) iter.Seq2[int, int] {
+	return func(yield func(int, int) bool) {
+		// h[x] to wartość funkcji hashBytesModN ostatnich
+		// `m.buckets[x].n` wczytanych bajtów. Jest zmienną lokalną,
+		// żeby z jednego obiektu mogło korzystać jednocześnie wiele
+		// iteratorów
+		h := make([]uint64, len(m.buckets))
+		for _, b := range m.buckets {
+			if b.n == 0 && !m.report(b, 0, text, 0, yield) {
+				return
+			}
+		}
+		for j, c := range text {
+			// Okna tekstu kończą się na pozycji j
+			for x, b := range m.buckets {
+				if b.n == 0 {
+					if !m.report(b, 0, text, j+1, yield) {
+						return
+					}
+					continue
+				}
+				h[x] = hashByteModN(c, h[x], N)
+				if j >= b.n {
+					h[x] = unhashByteModN(text[j-b.n], h[x], N, b.power)
+				}
+				if j+1 >= b.n && !m.report(b, h[x], text, j+1-b.n, yield) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// FindAll wywołuje `output(k, i)` dla każdej pary, po której
+// przechodzi iterator zwracany przez metodę All
+func (m *MultiKarpRabinMatcher) FindAll(text []byte, output func(int, int)) {
+	for k, i := range m.All(text) {
+		output(k, i)
+	}
+}
+
+// MultiKarpRabin wywołuje `output(k, i)` dla każdego takiego `k` i `i`,
+// że `slices.Equal(text[i:i+len(pats[k])], pats[k])`
+func MultiKarpRabin(pats [][]byte, text []byte, output func(int, int)) {
+	CompileMultiKarpRabin(pats).FindAll(text, output)
+}
+
+// MultiKarpRabinSeq zwraca iterator po parach, które funkcja
+// MultiKarpRabin przekazuje funkcji `output`
+func MultiKarpRabinSeq(pats [][]byte, text []byte) iter.Seq2[int, int] {
+	return CompileMultiKarpRabin(pats).All(text)
+}
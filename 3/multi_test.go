@@ -0,0 +1,202 @@
+package matching
+
+import (
+	"iter"
+	"math/rand"
+	"slices"
+	"testing"
+
+	ahocorasick "github.com/BobuSumisu/aho-corasick"
+)
+
+// multiIndices zwraca wycinek par (k, i) wystąpień wzorców `pats`
+// w tekście `text`, znalezionych algorytmem naiwnym
+func multiIndices(pats [][]byte, text []byte) [][2]int {
+	r := [][2]int{}
+	for k, pat := range pats {
+		for _, i := range indices(pat, text) {
+			r = append(r, [2]int{k, i})
+		}
+	}
+	return r
+}
+
+// multiKarpRabinIndices zwraca posortowany wycinek par (k, i)
+// wystąpień wzorców `pats` w tekście `text`, znalezionych funkcją
+// MultiKarpRabin
+func multiKarpRabinIndices(pats [][]byte, text []byte) [][2]int {
+	r := [][2]int{}
+	MultiKarpRabin(pats, text, func(k, i int) {
+		r = append(r, [2]int{k, i})
+	})
+	slices.SortFunc(r, func(a, b [2]int) int {
+		if a[0] != b[0] {
+			return a[0] - b[0]
+		}
+		return a[1] - b[1]
+	})
+	return r
+}
+
+func TestMultiKarpRabin(t *testing.T) {
+	data := []struct {
+		pats []string
+		text string
+	}{
+		{[]string{"he", "she", "his", "hers"}, "ushershishe"},
+		{[]string{"aa", "aa", "a", "aaa"}, "aaaa"},
+		{[]string{"", "ab"}, "abab"},
+		{[]string{"abcde"}, "abc"},
+		{[]string{}, "abc"},
+	}
+	for _, d := range data {
+		pats := [][]byte{}
+		for _, pat := range d.pats {
+			pats = append(pats, []byte(pat))
+		}
+		got := multiKarpRabinIndices(pats, []byte(d.text))
+		want := multiIndices(pats, []byte(d.text))
+		if !slices.Equal(got, want) {
+			t.Errorf("MultiKarpRabin(%#v, %#v) == %#v want %#v",
+				d.pats, d.text, got, want)
+		}
+	}
+}
+
+func TestMultiKarpRabinRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for range 300 {
+		k := 1 + r.Intn(3)
+		pats := [][]byte{}
+		for range r.Intn(8) {
+			pats = append(pats, randomBytes(r, 1+r.Intn(6), k))
+		}
+		text := randomBytes(r, r.Intn(200), k)
+		got := multiKarpRabinIndices(pats, text)
+		want := multiIndices(pats, text)
+		if !slices.Equal(got, want) {
+			t.Errorf("MultiKarpRabin(%q, %q) == %#v want %#v",
+				pats, text, got, want)
+		}
+	}
+}
+
+func TestMultiKarpRabinFullBytes(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for _, text := range [][]byte{randomFullBytes(r, 5000),
+		randomPolish(r, 3000)} {
+		for range 20 {
+			pats := plantedPatterns(r, text, 1+r.Intn(20), 100)
+			got := multiKarpRabinIndices(pats, text)
+			want := multiIndices(pats, text)
+			if !slices.Equal(got, want) {
+				t.Errorf("MultiKarpRabin(%q, ...) == %d occurrences "+
+					"want %d", pats, len(got), len(want))
+			}
+		}
+	}
+}
+
+func TestMultiKarpRabinSeqBreak(t *testing.T) {
+	pats := [][]byte{[]byte("a"), []byte("b")}
+	got := [][2]int{}
+	for k, i := range MultiKarpRabinSeq(pats, []byte("abab")) {
+		got = append(got, [2]int{k, i})
+		if len(got) == 2 {
+			break
+		}
+	}
+	if want := [][2]int{{0, 0}, {1, 1}}; !slices.Equal(got, want) {
+		t.Errorf("first 2 elements of MultiKarpRabinSeq == %#v want %#v",
+			got, want)
+	}
+}
+
+func TestMultiKarpRabinInterleaved(t *testing.T) {
+	pats := [][]byte{[]byte("ab"), []byte("abc"), []byte("b")}
+	m := CompileMultiKarpRabin(pats)
+	text1 := []byte("abcabxab")
+	text2 := []byte("xxbabcbb")
+	want1 := slices.Collect(func(yield func([2]int) bool) {
+		for k, i := range m.All(text1) {
+			yield([2]int{k, i})
+		}
+	})
+	want2 := slices.Collect(func(yield func([2]int) bool) {
+		for k, i := range m.All(text2) {
+			yield([2]int{k, i})
+		}
+	})
+	// Dwa iteratory tego samego obiektu, przeglądane na przemian
+	next1, stop1 := iter.Pull2(m.All(text1))
+	defer stop1()
+	next2, stop2 := iter.Pull2(m.All(text2))
+	defer stop2()
+	got1, got2 := [][2]int{}, [][2]int{}
+	for ok1, ok2 := true, true; ok1 || ok2; {
+		var k, i int
+		if k, i, ok1 = next1(); ok1 {
+			got1 = append(got1, [2]int{k, i})
+		}
+		if k, i, ok2 = next2(); ok2 {
+			got2 = append(got2, [2]int{k, i})
+		}
+	}
+	if !slices.Equal(got1, want1) || !slices.Equal(got2, want2) {
+		t.Errorf("interleaved MultiKarpRabinMatcher.All == %v, %v "+
+			"want %v, %v", got1, got2, want1, want2)
+	}
+}
+
+// geneWorkload zwraca losowy łańcuch DNA o długości 1 MB i 100
+// wyciętych z niego wzorców o długości od `minLen` do `maxLen`
+// nukleotydów, czyli takiej, jak długość genów mitochondrialnego DNA
+// z 5. zajęć, jeśli `minLen == 200` i `maxLen == 1000`
+func geneWorkload(minLen, maxLen int) ([][]byte, []byte) {
+	r := rand.New(rand.NewSource(1))
+	text := randomBytes(r, 1<<20, 4)
+	for i, c := range text {
+		text[i] = "ACGT"[c-'a']
+	}
+	pats := [][]byte{}
+	for range 100 {
+		n := minLen + r.Intn(maxLen-minLen+1)
+		i := r.Intn(len(text) - n)
+		pats = append(pats, text[i:i+n])
+	}
+	return pats, text
+}
+
+func benchmarkMultiKarpRabin(b *testing.B, minLen, maxLen int) {
+	pats, text := geneWorkload(minLen, maxLen)
+	b.SetBytes(int64(len(text)))
+	for b.Loop() {
+		MultiKarpRabin(pats, text, func(int, int) {})
+	}
+}
+
+func benchmarkAhoCorasick(b *testing.B, minLen, maxLen int) {
+	pats, text := geneWorkload(minLen, maxLen)
+	b.SetBytes(int64(len(text)))
+	for b.Loop() {
+		builder := ahocorasick.NewTrieBuilder()
+		builder.AddPatterns(pats)
+		builder.Build().Match(text)
+	}
+}
+
+func BenchmarkMultiKarpRabinEqualLengths(b *testing.B) {
+	benchmarkMultiKarpRabin(b, 32, 32)
+}
+
+func BenchmarkAhoCorasickEqualLengths(b *testing.B) {
+	benchmarkAhoCorasick(b, 32, 32)
+}
+
+func BenchmarkMultiKarpRabinGeneLengths(b *testing.B) {
+	benchmarkMultiKarpRabin(b, 200, 1000)
+}
+
+func BenchmarkAhoCorasickGeneLengths(b *testing.B) {
+	benchmarkAhoCorasick(b, 200, 1000)
+}
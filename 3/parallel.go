@@ -0,0 +1,62 @@
+package matching
+
+import (
+	"runtime"
+)
+
+// Liczba pozycji początkowych wystąpień wzorca w jednym fragmencie
+// tekstu, który przeszukuje funkcja ParallelFindAll
+var parallelChunkSize = 1 << 20
+
+// ParallelFindAll wywołuje `output(i)` dla każdego takiego `i`, że
+// `slices.Equal(text[i:i+len(pat)], pat)`, w kolejności rosnących `i`.
+// Dzieli tekst na fragmenty, które zachodzą na siebie o `len(pat)-1`
+// bajtów, i przeszukuje je współbieżnie w `runtime.GOMAXPROCS(0)`
+// gorutynach obiektem zwróconym przez funkcję `compile`, na przykład
+// jedną z funkcji zapisanych w zmiennej globalnej `Matchers`.
+// Przekazuje wystąpienia funkcji `output` na bieżąco, nie czekając
+// na przeszukanie całego tekstu
+func ParallelFindAll(compile func(pat []byte) Matcher, pat, text []byte,
+	output func(int)) {
+	m := compile(pat)
+	chunkSize := max(parallelChunkSize, 1)
+	// Fragment k zawiera początki wystąpień wzorca od k*chunkSize
+	// do (k+1)*chunkSize-1
+	chunks := max((len(text)-len(pat))/chunkSize+1, 1)
+	// Gorutyna, która przeszukuje fragment k, przesyła indeksy
+	// wystąpień kanałem results[k]. Kanały są opróżniane po kolei,
+	// więc w pamięci czeka najwyżej `cap(results[k])` indeksów
+	// na każdą gorutynę
+	results := make([]chan int, chunks)
+	for k := range results {
+		results[k] = make(chan int, 1024)
+	}
+	jobs := make(chan int)
+	for range min(runtime.GOMAXPROCS(0), chunks) {
+		go func() {
+			for k := range jobs {
+				start := k * chunkSize
+				end := min(start+chunkSize+len(pat)-1, len(text))
+				for i := range m.All(text[start:end]) {
+					// Pusty wzorzec występuje też na końcu
+					// fragmentu, czyli na początku następnego
+					if i < chunkSize || k == chunks-1 {
+						results[k] <- start + i
+					}
+				}
+				close(results[k])
+			}
+		}()
+	}
+	go func() {
+		for k := range chunks {
+			jobs <- k
+		}
+		close(jobs)
+	}()
+	for _, ch := range results {
+		for i := range ch {
+			output(i)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package matching
+
+import (
+	"fmt"
+	"maps"
+	"math/rand"
+	"runtime"
+	"slices"
+	"testing"
+)
+
+func TestParallelFindAll(t *testing.T) {
+	defer func(size int) { parallelChunkSize = size }(parallelChunkSize)
+	r := rand.New(rand.NewSource(1))
+	for range 200 {
+		k := 1 + r.Intn(3)
+		pat := randomBytes(r, r.Intn(8), k)
+		text := randomBytes(r, r.Intn(300), k)
+		parallelChunkSize = 1 + r.Intn(20)
+		for _, name := range slices.Sorted(maps.Keys(Matchers)) {
+			want := findAll(Matchers[name](pat), text)
+			got := []int{}
+			ParallelFindAll(Matchers[name], pat, text, func(i int) {
+				got = append(got, i)
+			})
+			if !slices.Equal(got, want) {
+				t.Errorf("%s: ParallelFindAll(%#v, %#v) == %#v want %#v "+
+					"(chunk size %d)", name, string(pat), string(text),
+					got, want, parallelChunkSize)
+			}
+		}
+	}
+}
+
+// BenchmarkParallelFindAll mierzy czas wyszukiwania wzorca w losowym
+// tekście o długości 64 MB dla różnych wartości GOMAXPROCS.
+// Przykład: go test -bench ParallelFindAll/bm/
+func BenchmarkParallelFindAll(b *testing.B) {
+	r := rand.New(rand.NewSource(1))
+	text := randomBytes(r, 64<<20, 26)
+	pat := text[len(text)/2 : len(text)/2+16]
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(0))
+	for _, name := range []string{"bm", "kmp", "shiftor", "horspool"} {
+		for _, procs := range []int{1, 2, 4, 8} {
+			b.Run(fmt.Sprintf("%s/procs=%d", name, procs), func(b *testing.B) {
+				runtime.GOMAXPROCS(procs)
+				b.SetBytes(int64(len(text)))
+				for b.Loop() {
+					ParallelFindAll(Matchers[name], pat, text, func(int) {})
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,186 @@
+package matching
+
+import (
+	"iter"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// RuneKMPPrefixFunction działa tak jak KMPPrefixFunction, ale dla
+// łańcucha runów
+func RuneKMPPrefixFunction(s []rune) []int {
+	p := make([]int, len(s)+1)
+	k := 0
+	for j := 1; j < len(s); j++ {
+		for k > 0 && s[j] != s[k] {
+			k = p[k]
+		}
+		if s[j] == s[k] {
+			k++
+		}
+		p[j+1] = k
+	}
+	return p
+}
+
+// RuneKMPSeq zwraca iterator po takich indeksach `i`, że
+// `slices.Equal(text[i:i+len(pat)], pat)`. Wyszukuje wzorzec
+// algorytmem Knutha-Morrisa-Pratta
+func RuneKMPSeq(pat, text []rune) iter.Seq[int] {
+	return func(yield func(int) bool) {
+		if len(pat) == 0 {
+			for i := 0; i <= len(text); i++ {
+				if !yield(i) {
+					return
+				}
+			}
+			return
+		}
+		p := RuneKMPPrefixFunction(pat)
+		j := 0
+		for i, c := range text {
+			for j > 0 && c != pat[j] {
+				j = p[j]
+			}
+			if c == pat[j] {
+				j++
+			}
+			if j == len(pat) {
+				if !yield(i - len(pat) + 1) {
+					return
+				}
+				j = p[j]
+			}
+		}
+	}
+}
+
+// RuneKMP wywołuje `output(i)` dla każdego takiego `i`,
+// że `slices.Equal(text[i:i+len(pat)], pat)`
+func RuneKMP(pat, text []rune, output func(int)) {
+	forEach(RuneKMPSeq(pat, text), output)
+}
+
+// runeMask to tablica masek algorytmu Shift-Or dla runów. Maski runów
+// mniejszych niż 256 są zapisane w tablicy, a maski pozostałych runów,
+// które występują we wzorcu - w mapie
+type runeMask struct {
+	latin1 [256]uint64
+	others map[rune]uint64
+}
+
+// makeRuneMask działa tak jak makeMask, ale dla łańcucha runów
+func makeRuneMask(pat []rune) *runeMask {
+	m := &runeMask{others: map[rune]uint64{}}
+	for c := range m.latin1 {
+		m.latin1[c] = ^uint64(0)
+	}
+	for j, c := range pat {
+		if c < 256 {
+			m.latin1[c] &^= setNthBit(j)
+			continue
+		}
+		if _, ok := m.others[c]; !ok {
+			m.others[c] = ^uint64(0)
+		}
+		m.others[c] &^= setNthBit(j)
+	}
+	return m
+}
+
+// get zwraca maskę runu `c`
+func (m *runeMask) get(c rune) uint64 {
+	if 0 <= c && c < 256 {
+		return m.latin1[c]
+	}
+	if mc, ok := m.others[c]; ok {
+		return mc
+	}
+	return ^uint64(0)
+}
+
+// RuneShiftOrSeq działa tak jak RuneKMPSeq, ale wyszukuje wzorce
+// o długości co najwyżej 64 runów algorytmem Shift-Or
+func RuneShiftOrSeq(pat, text []rune) iter.Seq[int] {
+	if len(pat) == 0 || len(pat) > 64 {
+		return RuneKMPSeq(pat, text)
+	}
+	return func(yield func(int) bool) {
+		m := makeRuneMask(pat)
+		s := ^uint64(0)
+		for i, c := range text {
+			s = (s << 1) | m.get(c)
+			if nthBit(s, len(pat)-1) == 0 && !yield(i-len(pat)+1) {
+				return
+			}
+		}
+	}
+}
+
+// RuneShiftOr wywołuje `output(i)` dla każdego takiego `i`,
+// że `slices.Equal(text[i:i+len(pat)], pat)`
+func RuneShiftOr(pat, text []rune, output func(int)) {
+	forEach(RuneShiftOrSeq(pat, text), output)
+}
+
+// FoldRune zmienia run `r` na małą literę bez znaków diakrytycznych,
+// tak jak funkcja ToASCIIString z 2. zajęć, na przykład 'Ł' na 'l',
+// a 'Ó' na 'o'
+func FoldRune(r rune) rune {
+	r = unicode.ToLower(r)
+	if r < utf8.RuneSelf {
+		return r
+	}
+	if r == 'ł' {
+		return 'l'
+	}
+	// Pierwszy run rozkładu kanonicznego NFD to litera bez znaków
+	// diakrytycznych
+	if d := norm.NFD.PropertiesString(string(r)).Decomposition(); d != nil {
+		r, _ = utf8.DecodeRune(d)
+	}
+	return r
+}
+
+// foldString zwraca runy łańcucha `s` zmienione funkcją FoldRune,
+// pomijając niesamodzielne znaki diakrytyczne, i indeksy bajtów `s`,
+// od których zaczynają się te runy. Ostatni indeks jest równy `len(s)`
+func foldString(s string) ([]rune, []int) {
+	folded := []rune{}
+	offsets := []int{}
+	for i, r := range s {
+		if unicode.Is(unicode.Mn, r) {
+			continue
+		}
+		folded = append(folded, FoldRune(r))
+		offsets = append(offsets, i)
+	}
+	return folded, append(offsets, len(s))
+}
+
+// FoldedFindSeq zwraca iterator po parach indeksów bajtów (początek,
+// koniec) tych wycinków tekstu `text`, które po zmianie funkcją
+// FoldRune są równe wzorcowi `pat` po takiej samej zmianie. Na
+// przykład wzorzec "lodz" występuje w tekście "Łódź". Koniec wycinka
+// obejmuje niesamodzielne znaki diakrytyczne po ostatnim runie
+func FoldedFindSeq(pat, text string) iter.Seq2[int, int] {
+	return func(yield func(int, int) bool) {
+		fpat, _ := foldString(pat)
+		ftext, offsets := foldString(text)
+		for i := range RuneShiftOrSeq(fpat, ftext) {
+			if !yield(offsets[i], offsets[i+len(fpat)]) {
+				return
+			}
+		}
+	}
+}
+
+// FoldedFind wywołuje `output(start, end)` dla każdej pary indeksów,
+// po której przechodzi iterator zwracany przez funkcję FoldedFindSeq
+func FoldedFind(pat, text string, output func(int, int)) {
+	for start, end := range FoldedFindSeq(pat, text) {
+		output(start, end)
+	}
+}
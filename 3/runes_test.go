@@ -0,0 +1,91 @@
+package matching
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+// runeIndices zwraca indeksy wszystkich wystąpień wzorca `pat`
+// w tekście `text`
+func runeIndices(pat, text []rune) []int {
+	r := []int{}
+	for i := 0; i+len(pat) <= len(text); i++ {
+		if slices.Equal(text[i:i+len(pat)], pat) {
+			r = append(r, i)
+		}
+	}
+	return r
+}
+
+// Runy, z których składają się losowe wzorce i teksty: litery
+// alfabetu łacińskiego i polskiego, runy spoza Latin-1 i run
+// o kodzie, który różni się od kodu litery 'a' o 256
+var testRunes = []rune{'a', 'b', 'ó', 'ł', 'ż', 'ą', 'a' + 256, '中'}
+
+func randomRunes(r *rand.Rand, n int) []rune {
+	rs := make([]rune, n)
+	for i := range rs {
+		rs[i] = testRunes[r.Intn(len(testRunes))]
+	}
+	return rs
+}
+
+func TestRuneMatchers(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for range 1000 {
+		pat := randomRunes(r, r.Intn(4))
+		text := randomRunes(r, r.Intn(100))
+		if r.Intn(20) == 0 {
+			pat = randomRunes(r, 65+r.Intn(10))
+			text = slices.Concat(text, pat, text)
+		}
+		want := runeIndices(pat, text)
+		if got := slices.Collect(RuneKMPSeq(pat, text)); !slices.Equal(got, want) {
+			t.Errorf("RuneKMP(%q, %q) == %#v want %#v", pat, text, got, want)
+		}
+		if got := slices.Collect(RuneShiftOrSeq(pat, text)); !slices.Equal(got, want) {
+			t.Errorf("RuneShiftOr(%q, %q) == %#v want %#v",
+				pat, text, got, want)
+		}
+	}
+}
+
+func TestFoldRune(t *testing.T) {
+	data := []struct {
+		in, want rune
+	}{
+		{'A', 'a'}, {'z', 'z'}, {'Ł', 'l'}, {'ł', 'l'}, {'Ó', 'o'},
+		{'ź', 'z'}, {'Ż', 'z'}, {'ę', 'e'}, {'Ą', 'a'}, {'ß', 'ß'},
+		{'中', '中'},
+	}
+	for _, d := range data {
+		if got := FoldRune(d.in); got != d.want {
+			t.Errorf("FoldRune(%q) == %q want %q", d.in, got, d.want)
+		}
+	}
+}
+
+func TestFoldedFind(t *testing.T) {
+	data := []struct {
+		pat, text string
+		want      []string
+	}{
+		{"lodz", "Łódź, ŁÓDŹ, lodz i łódka", []string{"Łódź", "ŁÓDŹ", "lodz"}},
+		{"Łódź", "do Lodzi", []string{"Lodz"}},
+		{"zolw", "Żółw i żółwie", []string{"Żółw", "żółw"}},
+		// Rozłożone litery: "o" i U+0301 oraz "z" i U+0301
+		{"lodz", "Lo\u0301dz\u0301!", []string{"Lo\u0301dz\u0301"}},
+		{"ab", "", []string{}},
+	}
+	for _, d := range data {
+		got := []string{}
+		FoldedFind(d.pat, d.text, func(start, end int) {
+			got = append(got, d.text[start:end])
+		})
+		if !slices.Equal(got, d.want) {
+			t.Errorf("FoldedFind(%#v, %#v) == %#v want %#v",
+				d.pat, d.text, got, d.want)
+		}
+	}
+}
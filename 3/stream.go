@@ -0,0 +1,124 @@
+package matching
+
+import (
+	"io"
+)
+
+// Rozmiar bufora, do którego funkcje *Stream wczytują kolejne
+// fragmenty tekstu
+var streamBufferSize = 64 << 10
+
+// streamFindAll wczytuje z `r` tekst w fragmentach o długości
+// `streamBufferSize` i wywołuje `output(i)` dla każdego indeksu `i`
+// wystąpienia w tym tekście wzorca o długości `n` wyszukiwanego przez
+// `m`. Kolejne fragmenty zachodzą na siebie o `n-1` bajtów, więc
+// wystąpienia na granicy fragmentów też zostają znalezione
+func streamFindAll(m Matcher, n int, r io.Reader, output func(int64)) error {
+	keep := max(n-1, 0)
+	buf := make([]byte, max(streamBufferSize, 2*n))
+	var offset int64 // Indeks bajtu buf[0] w całym tekście
+	k := 0           // Liczba bajtów w buforze
+	for {
+		nr, err := io.ReadFull(r, buf[k:])
+		k += nr
+		// Po błędzie nie będzie następnego fragmentu, ale bajty
+		// wczytane przed błędem też trzeba przeszukać
+		last := err != nil
+		m.FindAll(buf[:k], func(i int) {
+			// Pusty wzorzec występuje na końcu bufora i na
+			// początku następnego fragmentu: zgłoś go raz
+			if last || i < k {
+				output(offset + int64(i))
+			}
+		})
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		copy(buf, buf[k-keep:k])
+		offset += int64(k - keep)
+		k = keep
+	}
+}
+
+// KMPStream wywołuje `output(i)` dla każdego indeksu `i` wystąpienia
+// wzorca `pat` w tekście wczytywanym z `r`. Wyszukuje wzorzec
+// algorytmem Knutha-Morrisa-Pratta. Zwraca błąd, którym zakończyło się
+// czytanie z `r`, albo nil, jeśli czytanie zakończyło się błędem io.EOF
+func KMPStream(pat []byte, r io.Reader, output func(int64)) error {
+	if len(pat) == 0 {
+		return streamFindAll(CompileNaive(pat), 0, r, output)
+	}
+	m := kmpMatcher{pat, KMPPrefixFunction(pat)}
+	buf := make([]byte, streamBufferSize)
+	var offset int64
+	j := 0
+	for {
+		n, err := r.Read(buf)
+		j = m.findAllFrom(buf[:n], j, func(i int) bool {
+			output(offset + int64(i))
+			return true
+		})
+		offset += int64(n)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// ShiftOrStream działa tak jak KMPStream, ale wyszukuje wzorzec
+// algorytmem Shift-Or
+func ShiftOrStream(pat []byte, r io.Reader, output func(int64)) error {
+	if len(pat) == 0 {
+		return streamFindAll(CompileNaive(pat), 0, r, output)
+	}
+	// Funkcja, która przetwarza kolejny fragment tekstu, pamiętając
+	// maskę s między wywołaniami
+	var findAllFrom func(text []byte, yield func(int) bool)
+	if len(pat) <= 64 {
+		m := shiftOrMatcher{makeMask(pat), len(pat)}
+		s := ^uint64(0)
+		findAllFrom = func(text []byte, yield func(int) bool) {
+			s = m.findAllFrom(text, s, yield)
+		}
+	} else {
+		m := compileLongShiftOr(pat)
+		s := m.newState()
+		findAllFrom = func(text []byte, yield func(int) bool) {
+			m.findAllFrom(text, s, yield)
+		}
+	}
+	buf := make([]byte, streamBufferSize)
+	var offset int64
+	for {
+		n, err := r.Read(buf)
+		findAllFrom(buf[:n], func(i int) bool {
+			output(offset + int64(i))
+			return true
+		})
+		offset += int64(n)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// BoyerMooreStream działa tak jak KMPStream, ale wyszukuje wzorzec
+// algorytmem Boyera-Moore'a
+func BoyerMooreStream(pat []byte, r io.Reader, output func(int64)) error {
+	return streamFindAll(CompileBoyerMoore(pat), len(pat), r, output)
+}
+
+// KarpRabinStream działa tak jak KMPStream, ale wyszukuje wzorzec
+// algorytmem Karpa-Rabina
+func KarpRabinStream(pat []byte, r io.Reader, output func(int64)) error {
+	return streamFindAll(CompileKarpRabin(pat), len(pat), r, output)
+}
@@ -0,0 +1,116 @@
+package matching
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"math/rand"
+	"slices"
+	"testing"
+	"testing/iotest"
+)
+
+// Funkcje przeszukujące strumień i odpowiadające im funkcje
+// przeszukujące cały tekst w pamięci
+var streams = []struct {
+	name   string
+	stream func(pat []byte, r io.Reader, output func(int64)) error
+	find   func(pat, text []byte, output func(int))
+}{
+	{"KMPStream", KMPStream, KMP},
+	{"ShiftOrStream", ShiftOrStream, ShiftOr},
+	{"BoyerMooreStream", BoyerMooreStream, BoyerMoore},
+	{"KarpRabinStream", KarpRabinStream, KarpRabin},
+}
+
+// randomReader zwraca z każdym wywołaniem metody Read losową liczbę
+// bajtów tekstu `text`
+type randomReader struct {
+	text []byte
+	r    *rand.Rand
+}
+
+func (rr *randomReader) Read(p []byte) (int, error) {
+	if len(rr.text) == 0 {
+		return 0, io.EOF
+	}
+	n := copy(p[:1+rr.r.Intn(len(p))], rr.text)
+	rr.text = rr.text[n:]
+	return n, nil
+}
+
+func TestStreams(t *testing.T) {
+	defer func(size int) { streamBufferSize = size }(streamBufferSize)
+	r := rand.New(rand.NewSource(1))
+	for range 1000 {
+		k := 1 + r.Intn(3)
+		n := r.Intn(12)
+		if r.Intn(10) == 0 {
+			n = 60 + r.Intn(80) // Długi wzorzec dla ShiftOrStream
+		}
+		pat := randomBytes(r, n, k)
+		text := randomBytes(r, r.Intn(300), k)
+		streamBufferSize = 1 + r.Intn(20)
+		for _, s := range streams {
+			want := []int64{}
+			s.find(pat, text, func(i int) { want = append(want, int64(i)) })
+			got := []int64{}
+			rr := &randomReader{text, r}
+			err := s.stream(pat, rr, func(i int64) { got = append(got, i) })
+			if err != nil || !slices.Equal(got, want) {
+				t.Errorf("%s(%#v, %#v) == %#v, %v want %#v, nil "+
+					"(buffer size %d)", s.name, string(pat),
+					string(text), got, err, want, streamBufferSize)
+			}
+		}
+	}
+}
+
+func TestStreamsFullBytes(t *testing.T) {
+	defer func(size int) { streamBufferSize = size }(streamBufferSize)
+	r := rand.New(rand.NewSource(1))
+	for range 50 {
+		text := randomFullBytes(r, 2000)
+		pat := plantedPatterns(r, text, 1, 100)[0]
+		streamBufferSize = 1 + r.Intn(300)
+		for _, s := range streams {
+			want := []int64{}
+			s.find(pat, text, func(i int) { want = append(want, int64(i)) })
+			got := []int64{}
+			err := s.stream(pat, bytes.NewReader(text),
+				func(i int64) { got = append(got, i) })
+			if err != nil || !slices.Equal(got, want) ||
+				!slices.Equal(want, indicesInt64(pat, text)) {
+				t.Errorf("%s(%q, ...) == %v, %v want %v, nil",
+					s.name, pat, got, err, indicesInt64(pat, text))
+			}
+		}
+	}
+}
+
+// indicesInt64 zwraca indeksy wystąpień wzorca `pat` w tekście `text`
+// jako liczby typu int64
+func indicesInt64(pat, text []byte) []int64 {
+	r := []int64{}
+	for _, i := range indices(pat, text) {
+		r = append(r, int64(i))
+	}
+	return r
+}
+
+func TestStreamsError(t *testing.T) {
+	errRead := errors.New("read error")
+	for _, s := range streams {
+		r := io.MultiReader(bytes.NewReader([]byte("abcabc")),
+			iotest.ErrReader(errRead))
+		got := []int64{}
+		err := s.stream([]byte("bc"), r, func(i int64) { got = append(got, i) })
+		if !errors.Is(err, errRead) {
+			t.Errorf("%s returned error %v want %v", s.name, err, errRead)
+		}
+		// Wystąpienia we wczytanych przed błędem bajtach
+		if want := []int64{1, 4}; !slices.Equal(got, want) {
+			t.Errorf("%s reported %#v want %#v", s.name, got, want)
+		}
+	}
+}
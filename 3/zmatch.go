@@ -0,0 +1,140 @@
+package matching
+
+import (
+	"iter"
+	"slices"
+)
+
+// zMatcher wyszukuje wzorzec za pomocą funkcji Preprocess
+type zMatcher struct {
+	pat []byte
+}
+
+// CompileZMatch zwraca obiekt, który wyszukuje wzorzec `pat`
+// za pomocą funkcji Preprocess
+func CompileZMatch(pat []byte) Matcher {
+	if len(pat) == 0 {
+		return CompileNaive(pat)
+	}
+	return zMatcher{pat}
+}
+
+func (m zMatcher) All(text []byte) iter.Seq[int] {
+	return func(yield func(int) bool) {
+		// Łańcuch `pat+text` nie zawiera separatora, więc
+		// `z[n+i]` może być większe niż `n`
+		n := len(m.pat)
+		z := Preprocess(slices.Concat(m.pat, text))
+		for i := 0; i+n <= len(text); i++ {
+			if z[n+i] >= n && !yield(i) {
+				return
+			}
+		}
+	}
+}
+
+func (m zMatcher) FindAll(text []byte, output func(int)) {
+	forEach(m.All(text), output)
+}
+
+// ZMatch wywołuje `output(i)` dla każdego takiego `i`,
+// że `slices.Equal(text[i:i+len(pat)], pat)`
+func ZMatch(pat, text []byte, output func(int)) {
+	CompileZMatch(pat).FindAll(text, output)
+}
+
+// ZMatchSeq zwraca iterator po indeksach, które funkcja ZMatch
+// przekazuje funkcji `output`
+func ZMatchSeq(pat, text []byte) iter.Seq[int] {
+	return CompileZMatch(pat).All(text)
+}
+
+// Periods zwraca rosnący wycinek wszystkich okresów łańcucha `s`,
+// czyli takich liczb `p`, że `s[i] == s[i+p]` dla `0 <= i < len(s)-p`.
+// Ostatni element wycinka to `len(s)`
+func Periods(s []byte) []int {
+	z := Preprocess(s)
+	r := []int{}
+	for p := 1; p < len(s); p++ {
+		if z[p] == len(s)-p {
+			r = append(r, p)
+		}
+	}
+	return append(r, len(s))
+}
+
+// zAt zwraca `min(z[i], limit)` lub 0, jeśli `i` wykracza poza `z`
+func zAt(z []int, i, limit int) int {
+	if i < 0 || i >= len(z) {
+		return 0
+	}
+	return min(z[i], limit)
+}
+
+// TandemRepeats wywołuje `output(first, last, l)` dla takich trójek
+// liczb, że każdy wycinek `s[i:i+2*l]` dla `first <= i <= last` jest
+// powtórzeniem tandemowym, czyli `slices.Equal(s[i:i+l], s[i+l:i+2*l])`.
+// Każde powtórzenie tandemowe w `s` zostaje zgłoszone dokładnie raz.
+// Działa w czasie O(n log n) plus czas wywołań `output` algorytmem
+// Maina-Lorentza
+func TandemRepeats(s []byte, output func(int, int, int)) {
+	tandemRepeats(s, 0, output)
+}
+
+// tandemRepeats zgłasza powtórzenia tandemowe w łańcuchu `s`,
+// przesuwając ich indeksy o `shift`
+func tandemRepeats(s []byte, shift int, output func(int, int, int)) {
+	n := len(s)
+	if n <= 1 {
+		return
+	}
+	nu := n / 2
+	nv := n - nu
+	u, v := s[:nu], s[nu:]
+	tandemRepeats(u, shift, output)
+	tandemRepeats(v, shift+nu, output)
+	// Zgłoś powtórzenia, które zawierają zarówno koniec `u`, jak
+	// i początek `v`
+	ru := reversed(u)
+	rv := reversed(v)
+	z1 := Preprocess(ru)
+	z2 := Preprocess(slices.Concat(v, u))
+	z3 := Preprocess(slices.Concat(ru, rv))
+	z4 := Preprocess(v)
+	for cntr := range n {
+		// Druga połowa powtórzenia o długości 2*l zaczyna się na
+		// pozycji `cntr`, jeśli `cntr < nu`, a pierwsza połowa
+		// kończy się na pozycji `cntr` w przeciwnym razie
+		var l, k1, k2 int
+		if cntr < nu {
+			l = nu - cntr
+			// Najdłuższy wspólny sufiks `u[:cntr]` i `u`
+			k1 = zAt(z1, nu-cntr, nu)
+			// Najdłuższy wspólny prefiks `u[cntr:]` i `v`
+			k2 = zAt(z2, nv+cntr, nv)
+		} else {
+			l = cntr - nu + 1
+			// Najdłuższy wspólny sufiks `v[:cntr-nu+1]` i `u`
+			k1 = zAt(z3, nu+nv-1-(cntr-nu), nu)
+			// Najdłuższy wspólny prefiks `v[cntr-nu+1:]` i `v`
+			k2 = zAt(z4, cntr-nu+1, nv)
+		}
+		if k1+k2 < l {
+			continue
+		}
+		lo := max(1, l-k2)
+		hi := min(l, k1)
+		if cntr < nu && hi == l {
+			hi = l - 1
+		}
+		if lo > hi {
+			continue
+		}
+		// Początki powtórzeń dla `l1` od `lo` do `hi`
+		if cntr < nu {
+			output(shift+cntr-hi, shift+cntr-lo, l)
+		} else {
+			output(shift+cntr-l-hi+1, shift+cntr-l-lo+1, l)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package matching
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestPeriods(t *testing.T) {
+	data := []struct {
+		s    string
+		want []int
+	}{
+		{"", []int{0}},
+		{"a", []int{1}},
+		{"aaa", []int{1, 2, 3}},
+		{"abab", []int{2, 4}},
+		{"abaab", []int{3, 5}},
+		{"abaababaab", []int{5, 8, 10}},
+	}
+	for _, d := range data {
+		if got := Periods([]byte(d.s)); !slices.Equal(got, d.want) {
+			t.Errorf("Periods(%#v) == %#v want %#v", d.s, got, d.want)
+		}
+	}
+}
+
+// tandemRepeatsBruteForce zwraca posortowany wycinek par (i, l)
+// wszystkich powtórzeń tandemowych `s[i:i+2*l]`
+func tandemRepeatsBruteForce(s []byte) [][2]int {
+	r := [][2]int{}
+	for i := range s {
+		for l := 1; i+2*l <= len(s); l++ {
+			if slices.Equal(s[i:i+l], s[i+l:i+2*l]) {
+				r = append(r, [2]int{i, l})
+			}
+		}
+	}
+	return r
+}
+
+func TestTandemRepeats(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	data := [][]byte{
+		[]byte(""), []byte("a"), []byte("aa"), []byte("aaaaaaa"),
+		[]byte("abaababaabaab"), []byte("acababaee"),
+	}
+	for range 300 {
+		data = append(data, randomBytes(r, r.Intn(40), 1+r.Intn(3)))
+	}
+	for _, s := range data {
+		got := [][2]int{}
+		TandemRepeats(s, func(first, last, l int) {
+			for i := first; i <= last; i++ {
+				got = append(got, [2]int{i, l})
+			}
+		})
+		slices.SortFunc(got, func(a, b [2]int) int {
+			if a[0] != b[0] {
+				return a[0] - b[0]
+			}
+			return a[1] - b[1]
+		})
+		if want := tandemRepeatsBruteForce(s); !slices.Equal(got, want) {
+			t.Errorf("TandemRepeats(%#v) == %#v want %#v",
+				string(s), got, want)
+		}
+	}
+}
@@ -0,0 +1,271 @@
+package matching
+
+import (
+	"io"
+	"iter"
+)
+
+// AhoCorasickFlags to opcje automatu Aho-Corasick
+type AhoCorasickFlags int
+
+const (
+	// Zgłaszaj tylko rozłączne wystąpienia: spośród wystąpień,
+	// które zaczynają się najwcześniej, zgłoś najdłuższe
+	// i szukaj dalej za jego końcem
+	LeftmostLongest AhoCorasickFlags = 1 << iota
+	// Nie odróżniaj wielkich liter ASCII od małych
+	IgnoreCase
+)
+
+// Rozmiar bufora, do którego metoda FindReader wczytuje kolejne
+// fragmenty tekstu
+var ahoCorasickBufferSize = 64 << 10
+
+// AhoCorasickMatch to wystąpienie wzorca o indeksie `Pattern`,
+// które zaczyna się w tekście na pozycji `Pos`
+type AhoCorasickMatch struct {
+	Pattern int
+	Pos     int64
+}
+
+// AhoCorasick to automat, który wyszukuje jednocześnie wiele
+// wzorców. Przejścia automatu są zapisane w tablicy o 256
+// kolumnach, więc przejście po znaku nie wymaga liczenia
+// linków do najdłuższych sufiksów
+type AhoCorasick struct {
+	lens  []int // Długości wzorców
+	flags AhoCorasickFlags
+	// delta[256*s+c] to stan, do którego prowadzi ze stanu `s`
+	// znak `c`. Stan 0 to korzeń drzewa trie
+	delta []int32
+	// depth[s] to długość łańcucha, który odpowiada stanowi `s`
+	depth []int32
+	// outputs[s] to indeksy wzorców równych łańcuchowi stanu `s`
+	outputs [][]int
+	// dictLink[s] to stan najdłuższego takiego właściwego sufiksu
+	// łańcucha stanu `s`, który jest wzorcem, albo 0
+	dictLink []int32
+	maxLen   int // Długość najdłuższego wzorca
+}
+
+// toLowerASCII zamienia wielką literę ASCII `c` na małą
+func toLowerASCII(c byte) byte {
+	if 'A' <= c && c <= 'Z' {
+		return c + 'a' - 'A'
+	}
+	return c
+}
+
+// addState dodaje do automatu stan o głębokości `depth`
+// i zwraca jego numer
+func (a *AhoCorasick) addState(depth int32) int32 {
+	a.delta = append(a.delta, make([]int32, 256)...)
+	a.depth = append(a.depth, depth)
+	a.outputs = append(a.outputs, nil)
+	a.dictLink = append(a.dictLink, 0)
+	return int32(len(a.depth) - 1)
+}
+
+// NewAhoCorasick zwraca automat Aho-Corasick, który wyszukuje
+// wzorce `patterns` zgodnie z opcjami `flags`. Puste wzorce
+// nie mają wystąpień
+func NewAhoCorasick(patterns [][]byte, flags AhoCorasickFlags) *AhoCorasick {
+	a := &AhoCorasick{flags: flags}
+	a.addState(0)
+	// Zbuduj drzewo trie
+	for p, pat := range patterns {
+		a.lens = append(a.lens, len(pat))
+		a.maxLen = max(a.maxLen, len(pat))
+		if len(pat) == 0 {
+			continue
+		}
+		s := int32(0)
+		for _, c := range pat {
+			if flags&IgnoreCase != 0 {
+				c = toLowerASCII(c)
+			}
+			t := a.delta[256*int(s)+int(c)]
+			if t == 0 {
+				t = a.addState(a.depth[s] + 1)
+				a.delta[256*int(s)+int(c)] = t
+			}
+			s = t
+		}
+		a.outputs[s] = append(a.outputs[s], p)
+	}
+	// Przejdź drzewo wszerz. Linki do najdłuższych sufiksów
+	// wyznaczają brakujące przejścia i linki do wzorców
+	fail := make([]int32, len(a.depth))
+	queue := []int32{0}
+	for len(queue) > 0 {
+		s := queue[0]
+		queue = queue[1:]
+		row := a.delta[256*int(s) : 256*int(s)+256]
+		for c, t := range row {
+			if t != 0 {
+				if s != 0 {
+					fail[t] = a.delta[256*int(fail[s])+c]
+				}
+				if f := fail[t]; len(a.outputs[f]) > 0 {
+					a.dictLink[t] = f
+				} else {
+					a.dictLink[t] = a.dictLink[f]
+				}
+				queue = append(queue, t)
+			} else if s != 0 {
+				row[c] = a.delta[256*int(fail[s])+c]
+			}
+		}
+	}
+	if flags&IgnoreCase != 0 {
+		for s := range a.depth {
+			for c := 'A'; c <= 'Z'; c++ {
+				a.delta[256*s+int(c)] = a.delta[256*s+int(toLowerASCII(byte(c)))]
+			}
+		}
+	}
+	return a
+}
+
+// NewAhoCorasickStrings działa tak jak NewAhoCorasick, ale
+// przyjmuje wzorce jako łańcuchy
+func NewAhoCorasickStrings(patterns []string, flags AhoCorasickFlags) *AhoCorasick {
+	pats := make([][]byte, len(patterns))
+	for i, pat := range patterns {
+		pats[i] = []byte(pat)
+	}
+	return NewAhoCorasick(pats, flags)
+}
+
+// overlapping przechodzi automatem ze stanu `s` po tekście `text`,
+// który zaczyna się na pozycji `offset`, i przekazuje do `yield`
+// wszystkie wystąpienia wzorców, także nakładające się na siebie.
+// Zwraca stan po przeczytaniu tekstu i `false`, jeśli `yield`
+// zwróciła `false`
+func (a *AhoCorasick) overlapping(text []byte, s int32, offset int64,
+	yield func(AhoCorasickMatch) bool) (int32, bool) {
+	for i, c := range text {
+		s = a.delta[256*int(s)+int(c)]
+		for t := s; t != 0; t = a.dictLink[t] {
+			for _, p := range a.outputs[t] {
+				m := AhoCorasickMatch{p, offset + int64(i+1-a.lens[p])}
+				if !yield(m) {
+					return s, false
+				}
+			}
+		}
+	}
+	return s, true
+}
+
+// leftmostLongest przekazuje do `yield` rozłączne wystąpienia
+// wzorców w tekście `text`, który zaczyna się na pozycji `offset`,
+// wybierając spośród wystąpień najwcześniejszych najdłuższe. Jeśli
+// `final` jest równe `false`, to tekst może mieć dalszy ciąg.
+// Wtedy leftmostLongest zwraca indeks, od którego trzeba powtórzyć
+// wyszukiwanie po dołączeniu dalszego ciągu tekstu. Drugi wynik
+// jest równy `false`, jeśli `yield` zwróciła `false`
+func (a *AhoCorasick) leftmostLongest(text []byte, offset int64,
+	final bool, yield func(AhoCorasickMatch) bool) (int, bool) {
+	for i := 0; ; {
+		s := int32(0)
+		best, bestStart, bestEnd := -1, 0, 0
+		j := i
+		for ; j < len(text); j++ {
+			s = a.delta[256*int(s)+int(text[j])]
+			// Żadne wystąpienie, które zaczyna się nie później
+			// niż najlepsze, nie może się już skończyć
+			if best >= 0 && j+1-int(a.depth[s]) > bestStart {
+				break
+			}
+			t := s
+			if len(a.outputs[t]) == 0 {
+				t = a.dictLink[t]
+			}
+			if start := j + 1 - int(a.depth[t]); t != 0 &&
+				(best < 0 || start <= bestStart) {
+				best, bestStart, bestEnd = a.outputs[t][0], start, j+1
+			}
+		}
+		if j == len(text) && !final {
+			keep := j - int(a.depth[s])
+			if best >= 0 {
+				keep = min(keep, bestStart)
+			}
+			return keep, true
+		}
+		if best < 0 {
+			return len(text), true
+		}
+		if !yield(AhoCorasickMatch{best, offset + int64(bestStart)}) {
+			return 0, false
+		}
+		i = bestEnd
+	}
+}
+
+// All zwraca iterator po wystąpieniach wzorców w tekście `text`.
+// Bez opcji LeftmostLongest wystąpienia następują w kolejności
+// ich końców, a przy tym samym końcu - od najdłuższego
+func (a *AhoCorasick) All(text []byte) iter.Seq[AhoCorasickMatch] {
+	return func(yield func(AhoCorasickMatch) bool) {
+		if a.flags&LeftmostLongest != 0 {
+			a.leftmostLongest(text, 0, true, yield)
+		} else {
+			a.overlapping(text, 0, 0, yield)
+		}
+	}
+}
+
+// FindAll wywołuje funkcję `output(m)` dla każdego wystąpienia `m`
+// wzorca w tekście `text`
+func (a *AhoCorasick) FindAll(text []byte, output func(AhoCorasickMatch)) {
+	for m := range a.All(text) {
+		output(m)
+	}
+}
+
+// FindReader wywołuje funkcję `output(m)` dla każdego wystąpienia
+// `m` wzorca w tekście wczytywanym z `r`. Zwraca błąd, którym
+// zakończyło się czytanie z `r`, albo nil, jeśli czytanie
+// zakończyło się błędem io.EOF
+func (a *AhoCorasick) FindReader(r io.Reader, output func(AhoCorasickMatch)) error {
+	yield := func(m AhoCorasickMatch) bool {
+		output(m)
+		return true
+	}
+	buf := make([]byte, max(ahoCorasickBufferSize, 2*a.maxLen+1))
+	var offset int64 // Indeks bajtu buf[0] w całym tekście
+	if a.flags&LeftmostLongest == 0 {
+		s := int32(0)
+		for {
+			n, err := r.Read(buf)
+			s, _ = a.overlapping(buf[:n], s, offset, yield)
+			offset += int64(n)
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+	// Na początku bufora zostaje co najwyżej a.maxLen bajtów,
+	// których wystąpienia nie zostały jeszcze rozstrzygnięte
+	k := 0 // Liczba bajtów w buforze
+	for {
+		n, err := r.Read(buf[k:])
+		k += n
+		eof := err == io.EOF
+		if err != nil && !eof {
+			return err
+		}
+		keep, _ := a.leftmostLongest(buf[:k], offset, eof, yield)
+		if eof {
+			return nil
+		}
+		copy(buf, buf[keep:k])
+		offset += int64(keep)
+		k -= keep
+	}
+}
@@ -0,0 +1,204 @@
+package matching
+
+import (
+	"bytes"
+	"cmp"
+	"errors"
+	"io"
+	"math/rand"
+	"slices"
+	"testing"
+	"testing/iotest"
+)
+
+// repeatedBoyerMoore zwraca wystąpienia wszystkich wzorców
+// `pats` w tekście `text`, posortowane według początków, a przy
+// tym samym początku - od najdłuższego
+func repeatedBoyerMoore(pats [][]byte, text []byte) []AhoCorasickMatch {
+	r := []AhoCorasickMatch{}
+	for p, pat := range pats {
+		if len(pat) == 0 {
+			continue
+		}
+		BoyerMoore(pat, text, func(i int) {
+			r = append(r, AhoCorasickMatch{p, int64(i)})
+		})
+	}
+	sortByPos(pats, r)
+	return r
+}
+
+// leftmostLongest wybiera z wystąpień `all`, posortowanych tak jak
+// wynik funkcji repeatedBoyerMoore, rozłączne wystąpienia: spośród
+// najwcześniejszych najdłuższe
+func leftmostLongest(pats [][]byte, all []AhoCorasickMatch) []AhoCorasickMatch {
+	r := []AhoCorasickMatch{}
+	var end int64
+	for _, m := range all {
+		if m.Pos >= end {
+			r = append(r, m)
+			end = m.Pos + int64(len(pats[m.Pattern]))
+		}
+	}
+	return r
+}
+
+// sortByPos sortuje wystąpienia tak jak funkcja repeatedBoyerMoore
+func sortByPos(pats [][]byte, ms []AhoCorasickMatch) {
+	slices.SortFunc(ms, func(x, y AhoCorasickMatch) int {
+		return cmp.Or(cmp.Compare(x.Pos, y.Pos),
+			cmp.Compare(len(pats[y.Pattern]), len(pats[x.Pattern])),
+			cmp.Compare(x.Pattern, y.Pattern))
+	})
+}
+
+func randomPatterns(r *rand.Rand, alphabet string) ([][]byte, []byte) {
+	pats := make([][]byte, 1+r.Intn(8))
+	for p := range pats {
+		pats[p] = make([]byte, 1+r.Intn(6))
+		for i := range pats[p] {
+			pats[p][i] = alphabet[r.Intn(len(alphabet))]
+		}
+	}
+	text := make([]byte, r.Intn(100))
+	for i := range text {
+		text[i] = alphabet[r.Intn(len(alphabet))]
+	}
+	return pats, text
+}
+
+func TestNativeAhoCorasick(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for range 1000 {
+		pats, text := randomPatterns(r, "abc")
+		want := repeatedBoyerMoore(pats, text)
+		got := []AhoCorasickMatch{}
+		NewAhoCorasick(pats, 0).FindAll(text, func(m AhoCorasickMatch) {
+			got = append(got, m)
+		})
+		sortByPos(pats, got)
+		if !slices.Equal(got, want) {
+			t.Errorf("AhoCorasick(%q).FindAll(%q) == %v want %v",
+				pats, text, got, want)
+		}
+		got = []AhoCorasickMatch{}
+		a := NewAhoCorasick(pats, LeftmostLongest)
+		a.FindAll(text, func(m AhoCorasickMatch) { got = append(got, m) })
+		if want := leftmostLongest(pats, want); !slices.Equal(got, want) {
+			t.Errorf("AhoCorasick(%q, LeftmostLongest).FindAll(%q) == %v "+
+				"want %v", pats, text, got, want)
+		}
+	}
+}
+
+func TestNativeAhoCorasickIgnoreCase(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+	for range 1000 {
+		pats, text := randomPatterns(r, "aAbB")
+		lower := [][]byte{}
+		for _, pat := range pats {
+			lower = append(lower, bytes.ToLower(pat))
+		}
+		want := repeatedBoyerMoore(lower, bytes.ToLower(text))
+		got := []AhoCorasickMatch{}
+		NewAhoCorasick(pats, IgnoreCase).FindAll(text,
+			func(m AhoCorasickMatch) { got = append(got, m) })
+		sortByPos(pats, got)
+		if !slices.Equal(got, want) {
+			t.Errorf("AhoCorasick(%q, IgnoreCase).FindAll(%q) == %v want %v",
+				pats, text, got, want)
+		}
+	}
+}
+
+func TestNativeAhoCorasickExamples(t *testing.T) {
+	data := []struct {
+		pats  []string
+		text  string
+		flags AhoCorasickFlags
+		want  []AhoCorasickMatch
+	}{
+		{[]string{"he", "she", "his", "hers"}, "ushers", 0,
+			[]AhoCorasickMatch{{1, 1}, {0, 2}, {3, 2}}},
+		{[]string{"he", "she", "his", "hers"}, "ushers", LeftmostLongest,
+			[]AhoCorasickMatch{{1, 1}}},
+		{[]string{"bc", "abcd"}, "abcd", LeftmostLongest,
+			[]AhoCorasickMatch{{1, 0}}},
+		{[]string{"kot", "", "kot"}, "KOTy", IgnoreCase,
+			[]AhoCorasickMatch{{0, 0}, {2, 0}}},
+		{[]string{"kot", "kota"}, "Ala ma kota", LeftmostLongest | IgnoreCase,
+			[]AhoCorasickMatch{{1, 7}}},
+	}
+	for _, d := range data {
+		got := slices.Collect(NewAhoCorasickStrings(d.pats, d.flags).All(
+			[]byte(d.text)))
+		if !slices.Equal(got, d.want) {
+			t.Errorf("AhoCorasick(%q, %d).All(%q) == %v want %v",
+				d.pats, d.flags, d.text, got, d.want)
+		}
+	}
+}
+
+func TestNativeAhoCorasickReader(t *testing.T) {
+	defer func(size int) {
+		ahoCorasickBufferSize = size
+	}(ahoCorasickBufferSize)
+	r := rand.New(rand.NewSource(3))
+	for range 1000 {
+		pats, text := randomPatterns(r, "ab")
+		ahoCorasickBufferSize = 1 + r.Intn(10)
+		for _, flags := range []AhoCorasickFlags{0, LeftmostLongest} {
+			a := NewAhoCorasick(pats, flags)
+			want := slices.Collect(a.All(text))
+			for _, rd := range []io.Reader{
+				iotest.OneByteReader(bytes.NewReader(text)),
+				iotest.HalfReader(bytes.NewReader(text)),
+				iotest.DataErrReader(bytes.NewReader(text)),
+			} {
+				got := []AhoCorasickMatch{}
+				err := a.FindReader(rd, func(m AhoCorasickMatch) {
+					got = append(got, m)
+				})
+				if err != nil || !slices.Equal(got, want) {
+					t.Errorf("AhoCorasick(%q, %d).FindReader(%q) == %v, %v "+
+						"want %v", pats, flags, text, got, err, want)
+				}
+			}
+		}
+	}
+}
+
+func TestNativeAhoCorasickReaderError(t *testing.T) {
+	a := NewAhoCorasickStrings([]string{"ab"}, LeftmostLongest)
+	// Pierwsze czytanie się udaje, drugie kończy się błędem
+	rd := iotest.TimeoutReader(bytes.NewReader([]byte("abab")))
+	err := a.FindReader(rd, func(AhoCorasickMatch) {})
+	if !errors.Is(err, iotest.ErrTimeout) {
+		t.Errorf("FindReader zwraca błąd %v want %v", err, iotest.ErrTimeout)
+	}
+}
+
+// benchmarkWords to formy odmiany rzeczownika "dom"
+var benchmarkWords = []string{
+	"dom", "domu", "domowi", "domem", "domie", "domy", "domów",
+	"domom", "domami", "domach",
+}
+
+func BenchmarkNativeAhoCorasick(b *testing.B) {
+	text := benchmarkText()
+	b.SetBytes(int64(len(text)))
+	for b.Loop() {
+		a := NewAhoCorasickStrings(benchmarkWords, 0)
+		a.FindAll(text, func(AhoCorasickMatch) {})
+	}
+}
+
+func BenchmarkRepeatedBoyerMoore(b *testing.B) {
+	text := benchmarkText()
+	b.SetBytes(int64(len(text)))
+	for b.Loop() {
+		for _, pat := range benchmarkWords {
+			BoyerMoore([]byte(pat), text, func(int) {})
+		}
+	}
+}
@@ -0,0 +1,235 @@
+package matching
+
+import (
+	"iter"
+	"strings"
+)
+
+// EditOp to jedna operacja skryptu edycyjnego, który
+// przekształca wzorzec w wystąpienie wzorca w tekście
+type EditOp byte
+
+const (
+	Match      EditOp = '=' // Znak wzorca równy znakowi tekstu
+	Substitute EditOp = 'S' // Zamiana znaku wzorca na znak tekstu
+	Insert     EditOp = 'I' // Znak tekstu, którego nie ma we wzorcu
+	Delete     EditOp = 'D' // Znak wzorca, którego nie ma w tekście
+	Transpose  EditOp = 'T' // Dwa sąsiednie znaki wzorca zamienione miejscami
+)
+
+// EditScript to ciąg operacji edycyjnych
+type EditScript []EditOp
+
+// String zwraca skrypt edycyjny jako łańcuch, na przykład "==S=I="
+func (e EditScript) String() string {
+	var b strings.Builder
+	for _, op := range e {
+		b.WriteByte(byte(op))
+	}
+	return b.String()
+}
+
+// ApproximateMatch to przybliżone wystąpienie wzorca
+// w tekście: odległość Levenshteina (albo odległość OSA, jeśli
+// zwróciła je funkcja ApproximateMatchesD) między `text[Start:End]`
+// a wzorcem wynosi `Distance`
+type ApproximateMatch struct {
+	Start, End, Distance int
+}
+
+// bandedDistances zwraca tablicę `D`, w której `D[i][j]` to
+// odległość Levenshteina między `pat[len(pat)-i:]` a
+// `seg[len(seg)-j:]`, o ile `|i-j| <= d`. Pozostałe elementy
+// tablicy są większe od `d`. Tablica jest wypełniona od końców
+// łańcuchów, więc `D[len(pat)][j]` to odległość między wzorcem
+// a `j` ostatnimi znakami `seg`. Jeśli `osa == true`, tablica
+// zawiera odległości OSA, czyli zamiana miejscami dwóch sąsiednich
+// znaków jest jedną operacją
+func bandedDistances(pat, seg []byte, d int, osa bool) [][]int {
+	m, n := len(pat), len(seg)
+	D := make([][]int, m+1)
+	for i := range D {
+		D[i] = make([]int, n+1)
+		for j := range D[i] {
+			D[i][j] = d + 1
+		}
+	}
+	for i := 0; i <= m; i++ {
+		for j := max(0, i-d); j <= min(n, i+d); j++ {
+			switch {
+			case i == 0:
+				D[i][j] = j
+			case j == 0:
+				D[i][j] = i
+			default:
+				cost := 1
+				if pat[m-i] == seg[n-j] {
+					cost = 0
+				}
+				D[i][j] = min(D[i-1][j-1]+cost,
+					min(D[i-1][j], D[i][j-1])+1)
+				if osa && transposed(pat, seg, i, j) {
+					D[i][j] = min(D[i][j], D[i-2][j-2]+1)
+				}
+			}
+		}
+	}
+	return D
+}
+
+// transposed mówi, czy `pat[len(pat)-i:]` i `seg[len(seg)-j:]`
+// zaczynają się od tych samych dwóch znaków w odwrotnej kolejności
+func transposed(pat, seg []byte, i, j int) bool {
+	m, n := len(pat), len(seg)
+	return i > 1 && j > 1 && pat[m-i] == seg[n-j+1] &&
+		pat[m-i+1] == seg[n-j]
+}
+
+// approximateStart zwraca największy taki indeks `start`, że
+// odległość Levenshteina między `text[start:end]` a wzorcem `pat`
+// wynosi `d`, o ile ta odległość dla pewnego `start` wynosi `d`
+// i nie jest mniejsza dla żadnego innego. Jeśli `osa == true`,
+// zwraca początek wystąpienia odległego o `d` w sensie OSA
+func approximateStart(pat, text []byte, end, d int, osa bool) int {
+	lo := max(0, end-len(pat)-d)
+	D := bandedDistances(pat, text[lo:end], d, osa)
+	for j, dist := range D[len(pat)] {
+		if dist == d {
+			return end - j
+		}
+	}
+	return lo
+}
+
+// localMinima zwraca iterator po takich parach (i, d) z ciągu
+// `hits`, uporządkowanego według `i`, że `d` nie jest większe
+// od `d` par o indeksach `i-1` i `i+1`. Brak pary o danym indeksie
+// oznacza nieskończenie dużą wartość `d`
+func localMinima(hits iter.Seq2[int, int]) iter.Seq2[int, int] {
+	return func(yield func(int, int) bool) {
+		last, lastD := -2, 0
+		// Czy `lastD` nie jest większe od `d` pary o indeksie `last-1`
+		leftOK := false
+		for i, d := range hits {
+			next := i == last+1
+			if leftOK && (!next || lastD <= d) && !yield(last, lastD) {
+				return
+			}
+			leftOK = !next || d <= lastD
+			last, lastD = i, d
+		}
+		if leftOK {
+			yield(last, lastD)
+		}
+	}
+}
+
+// selectMatches zwraca iterator po przybliżonych wystąpieniach
+// wzorca `pat` w tekście `text`, wybranych z par (i, d), czyli
+// indeksów ostatnich znaków wystąpień i ich odległości od wzorca,
+// uporządkowanych według `i`. Wybiera pozycje, których odległość
+// nie jest większa od odległości sąsiednich pozycji, a spośród
+// możliwych początków wystąpienia - najpóźniejszy. Pomija
+// wystąpienie, które zawiera poprzednie wystąpienie i nie jest od
+// niego bliższe wzorca. Jeśli `osa == true`, `d` to odległości OSA
+func selectMatches(pat, text []byte, hits iter.Seq2[int, int],
+	osa bool) iter.Seq[ApproximateMatch] {
+	return func(yield func(ApproximateMatch) bool) {
+		prev := ApproximateMatch{Start: -1}
+		for i, d := range localMinima(hits) {
+			start := approximateStart(pat, text, i+1, d, osa)
+			m := ApproximateMatch{start, i + 1, d}
+			if m.Start <= prev.Start && m.Distance >= prev.Distance {
+				continue
+			}
+			if !yield(m) {
+				return
+			}
+			prev = m
+		}
+	}
+}
+
+// ApproximateMatchesSeq zwraca iterator po przybliżonych
+// wystąpieniach wzorca `pat` w tekście `text`, odległych od
+// wzorca co najwyżej o `k`, które funkcja selectMatches wybiera
+// spośród pozycji znalezionych przez funkcję FuzzyShiftOrLKSeq
+func ApproximateMatchesSeq(pat, text []byte, k int) iter.Seq[ApproximateMatch] {
+	return selectMatches(pat, text, FuzzyShiftOrLKSeq(pat, text, k), false)
+}
+
+// ApproximateMatches wywołuje funkcję `output(m)` dla każdego
+// przybliżonego wystąpienia `m` wzorca `pat` w tekście `text`,
+// które zwraca funkcja ApproximateMatchesSeq
+func ApproximateMatches(pat, text []byte, k int,
+	output func(ApproximateMatch)) {
+	for m := range ApproximateMatchesSeq(pat, text, k) {
+		output(m)
+	}
+}
+
+// ApproximateMatchesDSeq zwraca iterator po przybliżonych
+// wystąpieniach wzorca `pat` w tekście `text`, odległych od wzorca
+// w sensie OSA co najwyżej o `k`, które funkcja selectMatches wybiera
+// spośród pozycji znalezionych przez funkcję FuzzyShiftOrDKSeq
+func ApproximateMatchesDSeq(pat, text []byte, k int) iter.Seq[ApproximateMatch] {
+	return selectMatches(pat, text, FuzzyShiftOrDKSeq(pat, text, k), true)
+}
+
+// ApproximateMatchesD wywołuje funkcję `output(m)` dla każdego
+// przybliżonego wystąpienia `m` wzorca `pat` w tekście `text`,
+// które zwraca funkcja ApproximateMatchesDSeq
+func ApproximateMatchesD(pat, text []byte, k int,
+	output func(ApproximateMatch)) {
+	for m := range ApproximateMatchesDSeq(pat, text, k) {
+		output(m)
+	}
+}
+
+// Alignment zwraca skrypt edycyjny o `m.Distance` operacjach
+// różnych od Match, który przekształca wzorzec `pat`
+// w łańcuch `text[m.Start:m.End]`
+func Alignment(pat, text []byte, m ApproximateMatch) EditScript {
+	return alignment(pat, text, m, false)
+}
+
+// AlignmentD zwraca skrypt edycyjny o `m.Distance` operacjach
+// różnych od Match, który przekształca wzorzec `pat`
+// w łańcuch `text[m.Start:m.End]`, dla wystąpienia `m` zwróconego
+// przez funkcję ApproximateMatchesD. Skrypt może zawierać
+// operację Transpose
+func AlignmentD(pat, text []byte, m ApproximateMatch) EditScript {
+	return alignment(pat, text, m, true)
+}
+
+// alignment zwraca skrypt edycyjny dla funkcji Alignment
+// i AlignmentD
+func alignment(pat, text []byte, m ApproximateMatch, osa bool) EditScript {
+	seg := text[m.Start:m.End]
+	D := bandedDistances(pat, seg, m.Distance, osa)
+	script := EditScript{}
+	// Operacje odczytane od końca tablicy D następują po sobie
+	// w kolejności od początku wzorca
+	i, j := len(pat), len(seg)
+	for i > 0 || j > 0 {
+		switch {
+		case i > 0 && j > 0 && pat[len(pat)-i] == seg[len(seg)-j] &&
+			D[i-1][j-1] == D[i][j]:
+			script = append(script, Match)
+			i, j = i-1, j-1
+		case osa && transposed(pat, seg, i, j) && D[i-2][j-2]+1 == D[i][j]:
+			script = append(script, Transpose)
+			i, j = i-2, j-2
+		case i > 0 && j > 0 && D[i-1][j-1]+1 == D[i][j]:
+			script = append(script, Substitute)
+			i, j = i-1, j-1
+		case i > 0 && D[i-1][j]+1 == D[i][j]:
+			script = append(script, Delete)
+			i--
+		default:
+			script = append(script, Insert)
+			j--
+		}
+	}
+	return script
+}
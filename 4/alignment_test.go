@@ -0,0 +1,327 @@
+package matching
+
+import (
+	"bytes"
+	"math/rand"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// applyScript zwraca łańcuch, który powstaje z wzorca `pat`
+// po wykonaniu skryptu edycyjnego `script`, oraz liczbę operacji
+// różnych od Match. Znaki wstawione i zamienione bierze z `seg`
+func applyScript(pat, seg []byte, script EditScript) ([]byte, int) {
+	r := []byte{}
+	d := 0
+	i := 0
+	for _, op := range script {
+		switch op {
+		case Match:
+			r = append(r, pat[i])
+			i++
+		case Substitute:
+			r = append(r, seg[len(r)])
+			i++
+		case Insert:
+			r = append(r, seg[len(r)])
+		case Delete:
+			i++
+		case Transpose:
+			r = append(r, pat[i+1], pat[i])
+			i += 2
+		}
+		if op != Match {
+			d++
+		}
+	}
+	if i != len(pat) {
+		return nil, -1
+	}
+	return r, d
+}
+
+func TestApproximateMatches(t *testing.T) {
+	data := []struct {
+		pat, text string
+		k         int
+		want      []ApproximateMatch
+		scripts   []string
+	}{
+		{"domek", "dom domku domek", 2,
+			[]ApproximateMatch{{0, 3, 2}, {4, 8, 1}, {10, 15, 0}},
+			[]string{"===DD", "===D=", "====="}},
+		{"kot", "ala ma kota", 0,
+			[]ApproximateMatch{{7, 10, 0}}, []string{"==="}},
+		{"abcd", "xabxcdx", 1,
+			[]ApproximateMatch{{1, 6, 1}}, []string{"==I=="}},
+		{"abc", "xyz", 1, []ApproximateMatch{}, []string{}},
+		{"kot", "kot kot kot", 2,
+			[]ApproximateMatch{{0, 3, 0}, {4, 7, 0}, {8, 11, 0}},
+			[]string{"===", "===", "==="}},
+		{"ab", "abababab", 1,
+			[]ApproximateMatch{{0, 2, 0}, {2, 4, 0}, {4, 6, 0}, {6, 8, 0}},
+			[]string{"==", "==", "==", "=="}},
+	}
+	for _, d := range data {
+		got := []ApproximateMatch{}
+		scripts := []string{}
+		ApproximateMatches([]byte(d.pat), []byte(d.text), d.k,
+			func(m ApproximateMatch) {
+				got = append(got, m)
+				scripts = append(scripts,
+					Alignment([]byte(d.pat), []byte(d.text), m).String())
+			})
+		if !slices.Equal(got, d.want) || !slices.Equal(scripts, d.scripts) {
+			t.Errorf("ApproximateMatches(%q, %q, %d) == %v %q want %v %q",
+				d.pat, d.text, d.k, got, scripts, d.want, d.scripts)
+		}
+	}
+}
+
+// bruteForceApproximate zwraca przybliżone wystąpienia wzorca `pat`
+// w tekście `text`, odległe od wzorca co najwyżej o `k`, wyznaczone
+// wprost z definicji: ich końce to lokalne minima odległości, a
+// początki to najpóźniejsze początki o tej odległości. Pomija
+// wystąpienia, które zawierają poprzednie i nie są od niego bliższe
+// wzorca
+func bruteForceApproximate(pat, text []byte, k int) []ApproximateMatch {
+	return bruteForceApproximateDist(pat, text, k, levenshtein)
+}
+
+// bruteForceApproximateDist działa jak funkcja bruteForceApproximate,
+// ale mierzy odległość funkcją `distance`
+func bruteForceApproximateDist(pat, text []byte, k int,
+	distance func(s, t []byte) int) []ApproximateMatch {
+	// dist[e] to najmniejsza odległość wycinka `text[...:e]` od wzorca
+	dist := make([]int, len(text)+2)
+	starts := make([]int, len(text)+2)
+	dist[0], dist[len(text)+1] = len(pat)+k+1, len(pat)+k+1
+	for end := 1; end <= len(text); end++ {
+		dist[end] = len(pat) + 1
+		// Wystąpienie odległe od wzorca o `d <= k` ma co najwyżej
+		// len(pat)+k znaków. Dłuższe wycinki mają odległość
+		// większą niż k, więc nie zmieniają wyniku
+		for s := end; s >= max(end-len(pat)-k, 0); s-- {
+			if e := distance(text[s:end], pat); e < dist[end] {
+				starts[end], dist[end] = s, e
+			}
+		}
+	}
+	r := []ApproximateMatch{}
+	for end := 1; end <= len(text); end++ {
+		d := dist[end]
+		if d > k || d > dist[end-1] || d > dist[end+1] {
+			continue
+		}
+		if n := len(r); n > 0 && starts[end] <= r[n-1].Start &&
+			d >= r[n-1].Distance {
+			continue
+		}
+		r = append(r, ApproximateMatch{starts[end], end, d})
+	}
+	return r
+}
+
+func TestApproximateMatchesRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for range 300 {
+		pat := make([]byte, 1+r.Intn(8))
+		text := make([]byte, r.Intn(30))
+		for i := range pat {
+			pat[i] = "ab"[r.Intn(2)]
+		}
+		for i := range text {
+			text[i] = "abc"[r.Intn(3)]
+		}
+		exact := []ApproximateMatch{}
+		for i := range text {
+			if bytes.HasPrefix(text[i:], pat) {
+				exact = append(exact, ApproximateMatch{i, i + len(pat), 0})
+			}
+		}
+		for k := 0; k <= 4; k++ {
+			got := slices.Collect(ApproximateMatchesSeq(pat, text, k))
+			want := bruteForceApproximate(pat, text, k)
+			if !slices.Equal(got, want) {
+				t.Errorf("ApproximateMatchesSeq(%q, %q, %d) == %v want %v",
+					pat, text, k, got, want)
+			}
+			// Dokładne wystąpienia są też wystąpieniami przybliżonymi
+			for _, m := range exact {
+				if !slices.Contains(got, m) {
+					t.Errorf("ApproximateMatchesSeq(%q, %q, %d) == %v "+
+						"nie zawiera %v", pat, text, k, got, m)
+				}
+			}
+			for _, m := range got {
+				seg := text[m.Start:m.End]
+				script := Alignment(pat, text, m)
+				s, d := applyScript(pat, seg, script)
+				if !slices.Equal(s, seg) || d != m.Distance {
+					t.Errorf("Alignment(%q, %q, %v) == %v", pat, text,
+						m, script)
+				}
+			}
+		}
+	}
+}
+
+func TestApproximateMatchesD(t *testing.T) {
+	data := []struct {
+		pat, text string
+		k         int
+		want      []ApproximateMatch
+		scripts   []string
+	}{
+		{"domek", "dmoek domke", 1,
+			[]ApproximateMatch{{0, 5, 1}, {6, 10, 1}},
+			[]string{"=T==", "===D="}},
+		{"kolano", "x kloano x", 1,
+			[]ApproximateMatch{{2, 8, 1}}, []string{"=T==="}},
+		{"abcd", "badc", 1, []ApproximateMatch{}, []string{}},
+	}
+	for _, d := range data {
+		got := []ApproximateMatch{}
+		scripts := []string{}
+		ApproximateMatchesD([]byte(d.pat), []byte(d.text), d.k,
+			func(m ApproximateMatch) {
+				got = append(got, m)
+				scripts = append(scripts,
+					AlignmentD([]byte(d.pat), []byte(d.text), m).String())
+			})
+		if !slices.Equal(got, d.want) || !slices.Equal(scripts, d.scripts) {
+			t.Errorf("ApproximateMatchesD(%q, %q, %d) == %v %q want %v %q",
+				d.pat, d.text, d.k, got, scripts, d.want, d.scripts)
+		}
+	}
+}
+
+func TestApproximateMatchesDRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for range 300 {
+		pat := make([]byte, 1+r.Intn(8))
+		text := make([]byte, r.Intn(30))
+		for i := range pat {
+			pat[i] = "ab"[r.Intn(2)]
+		}
+		for i := range text {
+			text[i] = "abc"[r.Intn(3)]
+		}
+		for k := 0; k <= 4; k++ {
+			got := slices.Collect(ApproximateMatchesDSeq(pat, text, k))
+			want := bruteForceApproximateDist(pat, text, k, osa)
+			if !slices.Equal(got, want) {
+				t.Errorf("ApproximateMatchesDSeq(%q, %q, %d) == %v want %v",
+					pat, text, k, got, want)
+			}
+			for _, m := range got {
+				seg := text[m.Start:m.End]
+				script := AlignmentD(pat, text, m)
+				s, d := applyScript(pat, seg, script)
+				if !slices.Equal(s, seg) || d != m.Distance {
+					t.Errorf("AlignmentD(%q, %q, %v) == %v", pat, text,
+						m, script)
+				}
+			}
+		}
+	}
+}
+
+// Alfabety losowych tekstów: wszystkie bajty i litery alfabetu
+// polskiego zapisane w UTF-8
+var (
+	fullByteAlphabet = func() []string {
+		a := []string{}
+		for c := range 256 {
+			a = append(a, string([]byte{byte(c)}))
+		}
+		return a
+	}()
+	polishAlphabet = strings.Split("aąbcćdeęfghijklłmnńoóprsśtuwyzźż ", "")
+)
+
+// randomSymbols zwraca `n` losowych symboli alfabetu `alphabet`
+func randomSymbols(r *rand.Rand, alphabet []string, n int) []string {
+	s := []string{}
+	for range n {
+		s = append(s, alphabet[r.Intn(len(alphabet))])
+	}
+	return s
+}
+
+// mutateSymbols zwraca kopię ciągu symboli `s` po `e` losowych
+// zamianach, wstawieniach, usunięciach symboli i zamianach miejscami
+// sąsiednich symboli
+func mutateSymbols(r *rand.Rand, alphabet, s []string, e int) []string {
+	s = slices.Clone(s)
+	for range e {
+		i := r.Intn(len(s) + 1)
+		switch op := r.Intn(4); {
+		case op == 0 && i < len(s):
+			s[i] = alphabet[r.Intn(len(alphabet))]
+		case op == 1:
+			s = slices.Insert(s, i, alphabet[r.Intn(len(alphabet))])
+		case op == 2 && i < len(s):
+			s = slices.Delete(s, i, i+1)
+		case op == 3 && i+1 < len(s):
+			s[i], s[i+1] = s[i+1], s[i]
+		}
+	}
+	return s
+}
+
+// plantedWorkload zwraca wzorzec złożony z `n` symboli alfabetu
+// `alphabet` i losowy tekst, który zawiera ten wzorzec i jego kopię
+// po co najwyżej `e` edycjach
+func plantedWorkload(r *rand.Rand, alphabet []string, n, e int) ([]byte, []byte) {
+	pat := randomSymbols(r, alphabet, n)
+	text := slices.Concat(randomSymbols(r, alphabet, r.Intn(10)),
+		mutateSymbols(r, alphabet, pat, r.Intn(e+1)),
+		randomSymbols(r, alphabet, r.Intn(10)), pat,
+		randomSymbols(r, alphabet, r.Intn(10)))
+	return []byte(strings.Join(pat, "")), []byte(strings.Join(text, ""))
+}
+
+func TestApproximateMatchesAlphabets(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for _, alphabet := range [][]string{fullByteAlphabet, polishAlphabet} {
+		for range 100 {
+			pat, text := plantedWorkload(r, alphabet, 1+r.Intn(12), 3)
+			for k := 0; k <= 3; k++ {
+				got := slices.Collect(ApproximateMatchesSeq(pat, text, k))
+				want := bruteForceApproximate(pat, text, k)
+				if !slices.Equal(got, want) {
+					t.Errorf("ApproximateMatchesSeq(%q, %q, %d) == %v "+
+						"want %v", pat, text, k, got, want)
+				}
+			}
+		}
+	}
+}
+
+func TestApproximateMatchesDAlphabets(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for _, alphabet := range [][]string{fullByteAlphabet, polishAlphabet} {
+		for range 100 {
+			pat, text := plantedWorkload(r, alphabet, 1+r.Intn(12), 3)
+			for k := 0; k <= 3; k++ {
+				got := slices.Collect(ApproximateMatchesDSeq(pat, text, k))
+				want := bruteForceApproximateDist(pat, text, k, osa)
+				if !slices.Equal(got, want) {
+					t.Errorf("ApproximateMatchesDSeq(%q, %q, %d) == %v "+
+						"want %v", pat, text, k, got, want)
+				}
+				for _, m := range got {
+					seg := text[m.Start:m.End]
+					script := AlignmentD(pat, text, m)
+					s, d := applyScript(pat, seg, script)
+					if !slices.Equal(s, seg) || d != m.Distance {
+						t.Errorf("AlignmentD(%q, %q, %v) == %v", pat,
+							text, m, script)
+					}
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package matching
+
+import (
+	"bytes"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestComputeGoodSuffixes(t *testing.T) {
+	// Wszystkie wzorce nad alfabetem {a, b} o długości co najwyżej 12
+	for n := 1; n <= 12; n++ {
+		for bits := 0; bits < 1<<n; bits++ {
+			s := make([]byte, n)
+			for i := range s {
+				s[i] = 'a' + byte(bits>>i&1)
+			}
+			got := computeGoodSuffixes(s)
+			want := simpleComputeGoodSuffixes(s)
+			if !slices.Equal(got, want) {
+				t.Fatalf(`computeGoodSuffixes(%#v) == %#v want %#v`,
+					string(s), got, want)
+			}
+		}
+	}
+}
+
+func TestBoyerMoore(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for range 1000 {
+		pat := make([]byte, 1+r.Intn(6))
+		text := make([]byte, r.Intn(50))
+		for i := range pat {
+			pat[i] = "ab"[r.Intn(2)]
+		}
+		for i := range text {
+			text[i] = "ab"[r.Intn(2)]
+		}
+		want := []int{}
+		for i := range text {
+			if bytes.HasPrefix(text[i:], pat) {
+				want = append(want, i)
+			}
+		}
+		got := []int{}
+		BoyerMoore(pat, text, func(i int) { got = append(got, i) })
+		if !slices.Equal(got, want) {
+			t.Errorf("BoyerMoore(%q, %q) == %v want %v", pat, text, got, want)
+		}
+	}
+}
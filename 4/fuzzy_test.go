@@ -0,0 +1,234 @@
+package matching
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+// hamming zwraca liczbę pozycji, na których różnią się
+// łańcuchy `s` i `t` o równej długości
+func hamming(s, t []byte) int {
+	d := 0
+	for i := range s {
+		if s[i] != t[i] {
+			d++
+		}
+	}
+	return d
+}
+
+// levenshtein zwraca odległość Levenshteina łańcuchów `s` i `t`
+func levenshtein(s, t []byte) int {
+	row := make([]int, len(t)+1)
+	for j := range row {
+		row[j] = j
+	}
+	for i := range s {
+		diag := row[0]
+		row[0] = i + 1
+		for j := range t {
+			cost := 1
+			if s[i] == t[j] {
+				cost = 0
+			}
+			diag, row[j+1] = row[j+1], min(row[j+1]+1, row[j]+1, diag+cost)
+		}
+	}
+	return row[len(t)]
+}
+
+// osa zwraca odległość Damerau-Levenshteina łańcuchów `s` i `t`
+// w wersji OSA (optimal string alignment)
+func osa(s, t []byte) int {
+	D := make([][]int, len(s)+1)
+	for i := range D {
+		D[i] = make([]int, len(t)+1)
+		D[i][0] = i
+	}
+	for j := range D[0] {
+		D[0][j] = j
+	}
+	for i := 1; i <= len(s); i++ {
+		for j := 1; j <= len(t); j++ {
+			cost := 1
+			if s[i-1] == t[j-1] {
+				cost = 0
+			}
+			D[i][j] = min(D[i-1][j]+1, D[i][j-1]+1, D[i-1][j-1]+cost)
+			if i > 1 && j > 1 && s[i-1] == t[j-2] && s[i-2] == t[j-1] {
+				D[i][j] = min(D[i][j], D[i-2][j-2]+1)
+			}
+		}
+	}
+	return D[len(s)][len(t)]
+}
+
+// hit to wynik funkcji FuzzyShiftOrHK i FuzzyShiftOrLK
+type hit struct {
+	i, d int
+}
+
+func bruteForceH(pat, text []byte, k int) []hit {
+	r := []hit{}
+	for i := 0; i+len(pat) <= len(text); i++ {
+		if d := hamming(text[i:i+len(pat)], pat); d <= k {
+			r = append(r, hit{i, d})
+		}
+	}
+	return r
+}
+
+func bruteForceL(pat, text []byte, k int) []hit {
+	return bruteForce(pat, text, k, levenshtein)
+}
+
+func bruteForceD(pat, text []byte, k int) []hit {
+	return bruteForce(pat, text, k, osa)
+}
+
+// bruteForce zwraca takie pary (i, d), że najmniejsza odległość
+// `dist` między wycinkiem `text[...:i+1]` a wzorcem `pat` wynosi `d <= k`
+func bruteForce(pat, text []byte, k int, dist func(s, t []byte) int) []hit {
+	r := []hit{}
+	for i := range text {
+		d := len(pat)
+		for j := 0; j <= i+1; j++ {
+			d = min(d, dist(text[j:i+1], pat))
+		}
+		if d <= k {
+			r = append(r, hit{i, d})
+		}
+	}
+	return r
+}
+
+func TestFuzzyShiftOrK(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for range 300 {
+		pat := make([]byte, 1+r.Intn(8))
+		text := make([]byte, r.Intn(30))
+		for i := range pat {
+			pat[i] = "ab"[r.Intn(2)]
+		}
+		for i := range text {
+			text[i] = "abc"[r.Intn(3)]
+		}
+		for k := 0; k <= 4; k++ {
+			got := []hit{}
+			FuzzyShiftOrHK(pat, text, k, func(i, d int) {
+				got = append(got, hit{i, d})
+			})
+			if want := bruteForceH(pat, text, k); !slices.Equal(got, want) {
+				t.Errorf("FuzzyShiftOrHK(%q, %q, %d) == %v want %v",
+					pat, text, k, got, want)
+			}
+			got = []hit{}
+			FuzzyShiftOrLK(pat, text, k, func(i, d int) {
+				got = append(got, hit{i, d})
+			})
+			if want := bruteForceL(pat, text, k); !slices.Equal(got, want) {
+				t.Errorf("FuzzyShiftOrLK(%q, %q, %d) == %v want %v",
+					pat, text, k, got, want)
+			}
+			got = []hit{}
+			FuzzyShiftOrDK(pat, text, k, func(i, d int) {
+				got = append(got, hit{i, d})
+			})
+			if want := bruteForceD(pat, text, k); !slices.Equal(got, want) {
+				t.Errorf("FuzzyShiftOrDK(%q, %q, %d) == %v want %v",
+					pat, text, k, got, want)
+			}
+		}
+	}
+}
+
+// TestFuzzyShiftOrHL sprawdza wyniki funkcji FuzzyShiftOrH
+// i FuzzyShiftOrL z zajęć. W odróżnieniu od funkcji FuzzyShiftOrLK
+// z `k == 2` funkcja FuzzyShiftOrL nie zgłasza wystąpień, którym
+// brakuje początkowych znaków wzorca na początku tekstu
+func TestFuzzyShiftOrHL(t *testing.T) {
+	data := []struct {
+		pat, text string
+		h, l      []int
+	}{
+		{"domek", "dom domku domek", []int{0, 4, 10},
+			[]int{2, 3, 4, 6, 7, 8, 12, 13, 14}},
+		{"ab", "b", []int{}, []int{0}},
+		{"abc", "xbcx", []int{0}, []int{1, 2, 3}},
+		{"domek", "mek domek", []int{4}, []int{6, 7, 8}},
+		{"abcd", "cd", []int{}, []int{}},
+	}
+	for _, d := range data {
+		h := []int{}
+		FuzzyShiftOrH([]byte(d.pat), []byte(d.text), func(i int) {
+			h = append(h, i)
+		})
+		if !slices.Equal(h, d.h) {
+			t.Errorf("FuzzyShiftOrH(%q, %q) == %#v want %#v",
+				d.pat, d.text, h, d.h)
+		}
+		l := []int{}
+		FuzzyShiftOrL([]byte(d.pat), []byte(d.text), func(i int) {
+			l = append(l, i)
+		})
+		if !slices.Equal(l, d.l) {
+			t.Errorf("FuzzyShiftOrL(%q, %q) == %#v want %#v",
+				d.pat, d.text, l, d.l)
+		}
+	}
+}
+
+func TestFuzzyShiftOrDK(t *testing.T) {
+	data := []struct {
+		pat, text string
+		k         int
+		want      []hit
+	}{
+		{"kto", "tko", 1, []hit{{2, 1}}},
+		{"kto", "a tko b", 1, []hit{{4, 1}}},
+		{"abcd", "bacd", 1, []hit{{3, 1}}},
+		{"abcd", "badc", 1, []hit{}},
+		{"abcd", "badc", 2, []hit{{2, 2}, {3, 2}}},
+	}
+	for _, d := range data {
+		got := []hit{}
+		FuzzyShiftOrDK([]byte(d.pat), []byte(d.text), d.k, func(i, e int) {
+			got = append(got, hit{i, e})
+		})
+		if !slices.Equal(got, d.want) {
+			t.Errorf("FuzzyShiftOrDK(%q, %q, %d) == %v want %v",
+				d.pat, d.text, d.k, got, d.want)
+		}
+	}
+}
+
+func TestFuzzyShiftOrKPanics(t *testing.T) {
+	data := []struct {
+		pat string
+		k   int
+	}{
+		{"", 1},
+		{string(make([]byte, 65)), 1},
+		{"kot", -1},
+		{"kot", -2},
+	}
+	for _, d := range data {
+		for name, f := range map[string]func(pat, text []byte, k int,
+			output func(int, int)){
+			"FuzzyShiftOrHK": FuzzyShiftOrHK,
+			"FuzzyShiftOrLK": FuzzyShiftOrLK,
+			"FuzzyShiftOrDK": FuzzyShiftOrDK,
+		} {
+			func() {
+				defer func() {
+					if recover() == nil {
+						t.Errorf("%s(%q, ..., %d) did not panic",
+							name, d.pat, d.k)
+					}
+				}()
+				f([]byte(d.pat), []byte("kot"), d.k, func(int, int) {})
+			}()
+		}
+	}
+}
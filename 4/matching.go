@@ -1,9 +1,50 @@
 package matching
 
 import (
+	"iter"
 	"slices"
 )
 
+// lenOfCommonPrefix zwraca długość najdłuższego
+// wspólnego prefiksu łańcuchów `s` i `t`
+func lenOfCommonPrefix(s, t []byte) int {
+	k := 0
+	for ; k < min(len(s), len(t)); k++ {
+		if s[k] != t[k] {
+			return k
+		}
+	}
+	return k
+}
+
+// Preprocess zwraca wycinek. `k`-ty element tego wycinka
+// jest równy długości najdłuższego takiego prefiksu
+// łańcucha `s[k:]`, który jest równy pewnemu prefiksowi
+// łańcucha `s`
+func Preprocess(s []byte) []int {
+	z := make([]int, len(s))
+	l := 0
+	r := 0
+	for k := 1; k < len(s); k++ {
+		if k >= r {
+			z[k] = lenOfCommonPrefix(s[k:], s)
+			if z[k] > 0 {
+				l = k
+				r = k + z[k]
+			}
+		} else if z[k-l] >= r-k {
+			z[k] = r - k + lenOfCommonPrefix(s[r:], s[r-k:])
+			l = k
+			r = k + z[k]
+		} else {
+			z[k] = z[k-l]
+		}
+		// bytes.HasPrefix(s, s[k:k+z[k]])
+		// bytes.HasPrefix(s, s[l:r])
+	}
+	return z
+}
+
 // findLastOccurrences zwraca mapę, która:
 // + odwzorowuje wszystkie takie znaki, które występują
 //   w łańcuchu `s` na `k+1`, gdzie `k` to indeks ostatniego
@@ -41,6 +82,43 @@ func simpleComputeGoodSuffixes(s []byte) []int {
 	return r
 }
 
+// computeGoodSuffixes zwraca w czasie liniowym ten sam wycinek, co
+// funkcja simpleComputeGoodSuffixes. Korzysta z wartości funkcji
+// Preprocess dla wzorca `s` i dla odwróconego wzorca `s`
+func computeGoodSuffixes(s []byte) []int {
+	n := len(s)
+	r := make([]int, n)
+	// zr[n-1-j] to długość najdłuższego wspólnego sufiksu
+	// łańcuchów `s[:j+1]` i `s`
+	zr := Preprocess(reversed(s))
+	z := Preprocess(s)
+	// Przesunięcie o `k > i` pozycji: `s[k:]` jest prefiksem `s`
+	shift := n
+	for i := n - 1; i >= 0; i-- {
+		if i+1 < n && z[i+1] == n-i-1 {
+			shift = i + 1
+		}
+		r[i] = shift
+	}
+	// Przesunięcie o `k <= i` pozycji: sufiks `s[i+1:]` występuje
+	// też w `s` tak, że kończy się na pozycji `j = n-1-k`, a przed
+	// nim stoi znak różny od `s[i]`. Dla rosnących `j` wygrywa
+	// najmniejsze przesunięcie
+	for j := 0; j < n-1; j++ {
+		if l := zr[n-1-j]; l <= j {
+			r[n-1-l] = n - 1 - j
+		}
+	}
+	return r
+}
+
+// reversed zwraca odwrócony łańcuch `s`
+func reversed(s []byte) []byte {
+	r := slices.Clone(s)
+	slices.Reverse(r)
+	return r
+}
+
 // boyerMooreHasPrefix zwraca parę (R, S). R ma wartość `true`,
 // jeśli `slices.Equal(text[:len(pat)], pat)`; S określa,
 // o ile pozycji w prawo należy przesunąć wzorzec `pat`
@@ -55,18 +133,28 @@ func boyerMooreHasPrefix(text, pat []byte,
 	return true, goodSuffixes[0]
 }
 
+// BoyerMooreSeq zwraca iterator po takich indeksach `i`,
+// że `slices.Equal(text[i:i+len(pat)], pat)`
+func BoyerMooreSeq(pat, text []byte) iter.Seq[int] {
+	return func(yield func(int) bool) {
+		lastOccurrences := findLastOccurrences(pat)
+		goodSuffixes := computeGoodSuffixes(pat)
+		for i := 0; i+len(pat) <= len(text); /**/ {
+			found, shift := boyerMooreHasPrefix(text[i:], pat,
+				lastOccurrences, goodSuffixes)
+			if found && !yield(i) {
+				return
+			}
+			i += shift
+		}
+	}
+}
+
 // BoyerMoore wywołuje `output(i)` dla każdego takiego `i`,
 // że `slices.Equal(text[i:i+len(pat)], pat)`
 func BoyerMoore(pat, text []byte, output func(int)) {
-	lastOccurrences := findLastOccurrences(pat)
-	goodSuffixes := simpleComputeGoodSuffixes(pat)
-	for i := 0; i+len(pat) <= len(text); /**/ {
-		found, shift := boyerMooreHasPrefix(text[i:], pat,
-			lastOccurrences, goodSuffixes)
-		if found {
-			output(i)
-		}
-		i += shift
+	for i := range BoyerMooreSeq(pat, text) {
+		output(i)
 	}
 }
 
@@ -95,6 +183,77 @@ func makeMask(pat []byte) [256]uint64 {
 	return m
 }
 
+// checkShiftOr panikuje, jeśli wzorzec `pat` nie mieści się w masce
+// typu uint64 albo liczba błędów `k` jest ujemna
+func checkShiftOr(pat []byte, k int) {
+	if len(pat) == 0 || len(pat) > 64 {
+		panic("matching: wzorzec musi mieć od 1 do 64 bajtów")
+	}
+	if k < 0 {
+		panic("matching: liczba błędów nie może być ujemna")
+	}
+}
+
+// FuzzyShiftOrHKSeq zwraca iterator po takich parach (i, d), że
+// `text[i:i+len(pat)]` różni się od `pat` na `d <= k` pozycjach.
+// Para jest jedna dla każdego `i`; `d` to najmniejsza liczba błędów.
+// Wzorzec musi mieć od 1 do 64 bajtów, a `k` musi być nieujemne
+func FuzzyShiftOrHKSeq(pat, text []byte, k int) iter.Seq2[int, int] {
+	checkShiftOr(pat, k)
+	return func(yield func(int, int) bool) {
+		m := makeMask(pat)
+		// Bit j maski s[d] jest równy 0, jeśli `pat[:j+1]` różni
+		// się od ostatnich j+1 znaków tekstu na co najwyżej d pozycjach
+		s := make([]uint64, k+1)
+		for d := range s {
+			s[d] = ^uint64(0)
+		}
+		for i, c := range text {
+			// Uwzględnij zamianę 1 znaku; s[d-1] to jeszcze
+			// poprzednia wartość maski
+			for d := k; d > 0; d-- {
+				s[d] = ((s[d] << 1) | m[c]) & (s[d-1] << 1)
+			}
+			s[0] = (s[0] << 1) | m[c]
+			for d := range s {
+				if nthBit(s[d], len(pat)-1) == 0 {
+					if !yield(i-len(pat)+1, d) {
+						return
+					}
+					break
+				}
+			}
+		}
+	}
+}
+
+// FuzzyShiftOrHK wywołuje funkcję `output(i, d)` dla każdego
+// takiego indeksu `i`, że `text[i:i+len(pat)]` różni się
+// od `pat` na `d <= k` pozycjach
+func FuzzyShiftOrHK(pat, text []byte, k int, output func(int, int)) {
+	for i, d := range FuzzyShiftOrHKSeq(pat, text, k) {
+		output(i, d)
+	}
+}
+
+// FuzzyShiftOrHSeq zwraca iterator po indeksach, które funkcja
+// FuzzyShiftOrH przekazuje funkcji `output`
+func FuzzyShiftOrHSeq(pat, text []byte) iter.Seq[int] {
+	return func(yield func(int) bool) {
+		m := makeMask(pat)
+		s0, s1, s2 := ^uint64(0), ^uint64(0), ^uint64(0)
+		for i, c := range text {
+			// Uwzględnij zamianę 1 znaku
+			s2 = ((s2 << 1) | m[c]) & (s1 << 1)
+			s1 = ((s1 << 1) | m[c]) & (s0 << 1)
+			s0 = (s0 << 1) | m[c]
+			if nthBit(s2, len(pat)-1) == 0 && !yield(i-len(pat)+1) {
+				return
+			}
+		}
+	}
+}
+
 // FuzzyShiftOrH wywołuje funkcję `output(i)` dla każdego
 // takiego indeksu `i`, że `text[i:i+len(pat)]` różni się
 // od `pat` co najwyżej na 2 pozycjach
@@ -112,6 +271,76 @@ func FuzzyShiftOrH(pat, text []byte, output func(int)) {
 	}
 }
 
+// FuzzyShiftOrLKSeq zwraca iterator po takich parach (i, d), że
+// najmniejsza odległość Levenshteina między wycinkiem
+// `text[...:i+1]` a wzorcem `pat` wynosi `d <= k`. Wzorzec musi mieć
+// od 1 do 64 bajtów, a `k` musi być nieujemne
+func FuzzyShiftOrLKSeq(pat, text []byte, k int) iter.Seq2[int, int] {
+	checkShiftOr(pat, k)
+	return func(yield func(int, int) bool) {
+		m := makeMask(pat)
+		// Bit j maski s[d] jest równy 0, jeśli odległość
+		// Levenshteina między `pat[:j+1]` a pewnym sufiksem
+		// przeczytanego tekstu wynosi co najwyżej d. Przed
+		// początkiem tekstu wystarczy usunąć `pat[:d]`
+		s := make([]uint64, k+1)
+		for d := range s {
+			s[d] = ^uint64(0) << d
+		}
+		for i, c := range text {
+			prev := s[0]
+			s[0] = (s[0] << 1) | m[c]
+			for d := 1; d <= k; d++ {
+				cur := s[d]
+				// Uwzględnij zamianę 1 znaku, wstawienie 1 znaku
+				// i usunięcie 1 znaku
+				s[d] = ((cur << 1) | m[c]) & (prev << 1) & prev &
+					(s[d-1] << 1)
+				prev = cur
+			}
+			// Zwróć pozycję ostatniego znaku wycinka
+			for d := range s {
+				if nthBit(s[d], len(pat)-1) == 0 {
+					if !yield(i, d) {
+						return
+					}
+					break
+				}
+			}
+		}
+	}
+}
+
+// FuzzyShiftOrLK wywołuje funkcję `output(i, d)` dla każdego
+// takiego indeksu `i`, że najmniejsza odległość Levenshteina
+// między wycinkiem `text[...:i+1]` a wzorcem `pat` wynosi `d <= k`
+func FuzzyShiftOrLK(pat, text []byte, k int, output func(int, int)) {
+	for i, d := range FuzzyShiftOrLKSeq(pat, text, k) {
+		output(i, d)
+	}
+}
+
+// FuzzyShiftOrLSeq zwraca iterator po indeksach, które funkcja
+// FuzzyShiftOrL przekazuje funkcji `output`
+func FuzzyShiftOrLSeq(pat, text []byte) iter.Seq[int] {
+	return func(yield func(int) bool) {
+		m := makeMask(pat)
+		s0, s1, s2 := ^uint64(0), ^uint64(0), ^uint64(0)
+		for i, c := range text {
+			// Uwzględnij zamianę 1 znaku lub wstawienie 1 znaku
+			s2 = ((s2 << 1) | m[c]) & (s1 << 1) & s1
+			s1 = ((s1 << 1) | m[c]) & (s0 << 1) & s0
+			s0 = (s0 << 1) | m[c]
+			// Uwzględnij usunięcie 1 znaku
+			s1 &= (s0 << 1)
+			s2 &= (s1 << 1)
+			if nthBit(s2, len(pat)-1) == 0 && !yield(i) {
+				return
+			}
+		}
+	}
+}
+
 // FuzzyShiftOrL wywołuje funkcję `output(i)` dla każdego takiego
 // indeksu `i`, że odległość Levenshteina między pewnym wycinkiem
 // `text[...:i+1]` a wzorcem `pat` wynosi co najwyżej 2
@@ -131,3 +360,62 @@ func FuzzyShiftOrL(pat, text []byte, output func(int)) {
 		}
 	}
 }
+
+// FuzzyShiftOrDKSeq zwraca iterator po takich parach (i, d), że
+// najmniejsza odległość Damerau-Levenshteina (w wersji OSA, czyli
+// bez edycji transponowanych znaków) między wycinkiem
+// `text[...:i+1]` a wzorcem `pat` wynosi `d <= k`. Zamiana
+// miejscami dwóch sąsiednich znaków to jeden błąd. Wzorzec musi mieć
+// od 1 do 64 bajtów, a `k` musi być nieujemne
+func FuzzyShiftOrDKSeq(pat, text []byte, k int) iter.Seq2[int, int] {
+	checkShiftOr(pat, k)
+	return func(yield func(int, int) bool) {
+		m := makeMask(pat)
+		// s[d] to maski po przeczytaniu `text[:i]`, a t[d] - po
+		// przeczytaniu `text[:i-1]`
+		s := make([]uint64, k+1)
+		t := make([]uint64, k+1)
+		for d := range s {
+			s[d] = ^uint64(0) << d
+			t[d] = s[d]
+		}
+		for i, c := range text {
+			prev := s[0]
+			s[0] = (s[0] << 1) | m[c]
+			for d := 1; d <= k; d++ {
+				cur := s[d]
+				// Uwzględnij zamianę 1 znaku, wstawienie 1 znaku
+				// i usunięcie 1 znaku
+				s[d] = ((cur << 1) | m[c]) & (prev << 1) & prev &
+					(s[d-1] << 1)
+				// Uwzględnij zamianę miejscami znaków
+				// `text[i-1]` i `text[i]`
+				if i > 0 {
+					s[d] &= (t[d-1] << 2) | m[text[i-1]] | (m[c] << 1) | 1
+				}
+				t[d-1] = prev
+				prev = cur
+			}
+			t[k] = prev
+			// Zwróć pozycję ostatniego znaku wycinka
+			for d := range s {
+				if nthBit(s[d], len(pat)-1) == 0 {
+					if !yield(i, d) {
+						return
+					}
+					break
+				}
+			}
+		}
+	}
+}
+
+// FuzzyShiftOrDK wywołuje funkcję `output(i, d)` dla każdego
+// takiego indeksu `i`, że najmniejsza odległość Damerau-Levenshteina
+// (OSA) między wycinkiem `text[...:i+1]` a wzorcem `pat` wynosi
+// `d <= k`
+func FuzzyShiftOrDK(pat, text []byte, k int, output func(int, int)) {
+	for i, d := range FuzzyShiftOrDKSeq(pat, text, k) {
+		output(i, d)
+	}
+}
@@ -9,6 +9,16 @@ import (
 	"github.com/BobuSumisu/aho-corasick"
 )
 
+// Dzięki tym przypisaniom pakiet kompiluje się, zanim napiszą
+// państwo funkcje, które korzystają z zaimportowanych pakietów
+var (
+	_ = fmt.Printf
+	_ = log.Fatal
+	_ = os.ReadFile
+	_ = slices.Equal[[]int64]
+	_ = ahocorasick.NewTrieBuilder
+)
+
 var words = []string{
 }
 
@@ -0,0 +1,156 @@
+package matching
+
+import (
+	"cmp"
+	"iter"
+	"slices"
+)
+
+// MultiApproximateMatch to przybliżone wystąpienie wzorca
+// o indeksie `Pattern`: odległość Levenshteina między
+// `text[Start:End]` a tym wzorcem wynosi `Distance`
+type MultiApproximateMatch struct {
+	Pattern, Start, End, Distance int
+}
+
+// piece to fragment wzorca o indeksie `pattern`, który
+// zaczyna się we wzorcu na pozycji `offset`
+type piece struct {
+	pattern, offset int
+}
+
+// MultiApproximate wyszukuje jednocześnie przybliżone wystąpienia
+// wielu wzorców. Dzieli każdy wzorzec na k+1 fragmentów. Wystąpienie
+// wzorca odległe od niego co najwyżej o k zawiera dokładne wystąpienie
+// któregoś fragmentu, więc wystarczy sprawdzić otoczenie wystąpień
+// fragmentów, które znajduje automat Aho-Corasick
+type MultiApproximate struct {
+	pats   [][]byte
+	k      int
+	ac     *AhoCorasick
+	pieces []piece // pieces[i] to i-ty wzorzec automatu `ac`
+	// Maski znaków wzorców dla algorytmu Myersa
+	peqs []*[256][]uint64
+	// Wzorce krótsze niż k+1, których nie da się podzielić
+	// na k+1 niepustych fragmentów
+	short []int
+}
+
+// NewMultiApproximate zwraca obiekt, który wyszukuje wystąpienia
+// wzorców `patterns` odległe od nich co najwyżej o `k`. Puste
+// wzorce nie mają wystąpień
+func NewMultiApproximate(patterns [][]byte, k int) *MultiApproximate {
+	m := &MultiApproximate{pats: patterns, k: k}
+	m.peqs = make([]*[256][]uint64, len(patterns))
+	acPats := [][]byte{}
+	for p, pat := range patterns {
+		switch {
+		case len(pat) == 0:
+		case len(pat) < k+1:
+			m.short = append(m.short, p)
+		default:
+			m.peqs[p] = makePeq(pat)
+			for j := range k + 1 {
+				from, to := j*len(pat)/(k+1), (j+1)*len(pat)/(k+1)
+				acPats = append(acPats, pat[from:to])
+				m.pieces = append(m.pieces, piece{p, from})
+			}
+		}
+	}
+	m.ac = NewAhoCorasick(acPats, 0)
+	return m
+}
+
+// NewMultiApproximateStrings działa tak jak NewMultiApproximate,
+// ale przyjmuje wzorce jako łańcuchy
+func NewMultiApproximateStrings(patterns []string, k int) *MultiApproximate {
+	pats := make([][]byte, len(patterns))
+	for i, pat := range patterns {
+		pats[i] = []byte(pat)
+	}
+	return NewMultiApproximate(pats, k)
+}
+
+// hits zwraca iterator po parach (i, d), gdzie `i` to indeks
+// tekstu `text`, który kończy się w jednym z rozłącznych,
+// uporządkowanych przedziałów `ranges`, a `d <= k` to najmniejsza
+// odległość Levenshteina między wzorcem o indeksie `p` a wycinkiem
+// `text[...:i+1]`, który zaczyna się w tym przedziale
+func (m *MultiApproximate) hits(p int, text []byte,
+	ranges [][2]int) iter.Seq2[int, int] {
+	return func(yield func(int, int) bool) {
+		for _, r := range ranges {
+			lo, hi := r[0], r[1]
+			for i, d := range myersSeq(m.peqs[p], len(m.pats[p]), text[lo:hi]) {
+				if d <= m.k && !yield(lo+i, d) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// All zwraca iterator po przybliżonych wystąpieniach wzorców
+// w tekście `text` w kolejności ich końców. Dla każdego wzorca
+// wybiera wystąpienia tak samo, jak funkcja ApproximateMatchesSeq
+func (m *MultiApproximate) All(text []byte) iter.Seq[MultiApproximateMatch] {
+	return func(yield func(MultiApproximateMatch) bool) {
+		// Przedziały tekstu, w których trzeba szukać wystąpień
+		// kolejnych wzorców
+		intervals := make([][][2]int, len(m.pats))
+		for h := range m.ac.All(text) {
+			pc := m.pieces[h.Pattern]
+			begin := int(h.Pos) - pc.offset
+			lo := max(begin-m.k, 0)
+			hi := min(begin+len(m.pats[pc.pattern])+m.k, len(text))
+			intervals[pc.pattern] = append(intervals[pc.pattern],
+				[2]int{lo, hi})
+		}
+		r := []MultiApproximateMatch{}
+		for p, iv := range intervals {
+			slices.SortFunc(iv, func(x, y [2]int) int {
+				return cmp.Compare(x[0], y[0])
+			})
+			// Połącz przedziały, które zachodzą na siebie lub się
+			// stykają. Każde wystąpienie wzorca leży wtedy w całości
+			// w jednym przedziale
+			ranges := [][2]int{}
+			for i := 0; i < len(iv); {
+				lo, hi := iv[i][0], iv[i][1]
+				for i++; i < len(iv) && iv[i][0] <= hi; i++ {
+					hi = max(hi, iv[i][1])
+				}
+				ranges = append(ranges, [2]int{lo, hi})
+			}
+			for a := range selectMatches(m.pats[p], text,
+				m.hits(p, text, ranges), false) {
+				r = append(r, MultiApproximateMatch{p, a.Start, a.End,
+					a.Distance})
+			}
+		}
+		for _, p := range m.short {
+			for a := range ApproximateMatchesSeq(m.pats[p], text, m.k) {
+				r = append(r, MultiApproximateMatch{p, a.Start, a.End,
+					a.Distance})
+			}
+		}
+		slices.SortFunc(r, func(x, y MultiApproximateMatch) int {
+			return cmp.Or(cmp.Compare(x.End, y.End),
+				cmp.Compare(x.Pattern, y.Pattern))
+		})
+		for _, a := range r {
+			if !yield(a) {
+				return
+			}
+		}
+	}
+}
+
+// FindAll wywołuje funkcję `output(a)` dla każdego przybliżonego
+// wystąpienia `a` wzorca w tekście `text`
+func (m *MultiApproximate) FindAll(text []byte,
+	output func(MultiApproximateMatch)) {
+	for a := range m.All(text) {
+		output(a)
+	}
+}
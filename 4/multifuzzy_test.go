@@ -0,0 +1,106 @@
+package matching
+
+import (
+	"cmp"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+// bruteForceMultiApproximate zwraca przybliżone wystąpienia wzorców
+// `pats`, które znajduje dla każdego wzorca funkcja
+// bruteForceApproximate, w kolejności ich końców
+func bruteForceMultiApproximate(pats [][]byte, text []byte,
+	k int) []MultiApproximateMatch {
+	r := []MultiApproximateMatch{}
+	for p, pat := range pats {
+		if len(pat) == 0 {
+			continue
+		}
+		for _, a := range bruteForceApproximate(pat, text, k) {
+			r = append(r, MultiApproximateMatch{p, a.Start, a.End,
+				a.Distance})
+		}
+	}
+	slices.SortFunc(r, func(x, y MultiApproximateMatch) int {
+		return cmp.Or(cmp.Compare(x.End, y.End),
+			cmp.Compare(x.Pattern, y.Pattern))
+	})
+	return r
+}
+
+func TestMultiApproximate(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for range 500 {
+		pats, text := randomPatterns(r, "abc")
+		for _, pat := range pats[:r.Intn(len(pats))] {
+			pats = append(pats, slices.Concat(pat, pat, pat))
+		}
+		text = append(text, text...)
+		for k := 0; k <= 3; k++ {
+			got := slices.Collect(NewMultiApproximate(pats, k).All(text))
+			want := bruteForceMultiApproximate(pats, text, k)
+			if !slices.Equal(got, want) {
+				t.Errorf("MultiApproximate(%q, %d).All(%q) == %v want %v",
+					pats, k, text, got, want)
+			}
+		}
+	}
+}
+
+func TestMultiApproximateAlphabets(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for _, alphabet := range [][]string{fullByteAlphabet, polishAlphabet} {
+		for range 50 {
+			pats, text := [][]byte{}, []byte{}
+			for range 1 + r.Intn(4) {
+				pat, part := plantedWorkload(r, alphabet, 1+r.Intn(12), 3)
+				pats = append(pats, pat)
+				text = append(text, part...)
+			}
+			for k := 0; k <= 3; k++ {
+				got := slices.Collect(NewMultiApproximate(pats, k).All(text))
+				want := bruteForceMultiApproximate(pats, text, k)
+				if !slices.Equal(got, want) {
+					t.Errorf("MultiApproximate(%q, %d).All(%q) == %v "+
+						"want %v", pats, k, text, got, want)
+				}
+			}
+		}
+	}
+}
+
+func TestMultiApproximateExamples(t *testing.T) {
+	pats := []string{"dom", "domu", "domem", "domach", ""}
+	text := "w dmou i w domahc"
+	want := []MultiApproximateMatch{
+		{0, 2, 4, 1}, {1, 2, 4, 2}, {0, 4, 6, 2}, {1, 4, 6, 2},
+		{0, 11, 14, 0}, {1, 11, 14, 1}, {2, 11, 14, 2}, {3, 11, 16, 1},
+	}
+	got := []MultiApproximateMatch{}
+	NewMultiApproximateStrings(pats, 2).FindAll([]byte(text),
+		func(a MultiApproximateMatch) { got = append(got, a) })
+	if !slices.Equal(got, want) {
+		t.Errorf("MultiApproximate(%q, 2).FindAll(%q) == %v want %v",
+			pats, text, got, want)
+	}
+}
+
+func BenchmarkMultiApproximate(b *testing.B) {
+	text := benchmarkText()
+	b.SetBytes(int64(len(text)))
+	for b.Loop() {
+		m := NewMultiApproximateStrings(benchmarkWords, 1)
+		m.FindAll(text, func(MultiApproximateMatch) {})
+	}
+}
+
+func BenchmarkRepeatedFuzzyShiftOrLK(b *testing.B) {
+	text := benchmarkText()
+	b.SetBytes(int64(len(text)))
+	for b.Loop() {
+		for _, pat := range benchmarkWords {
+			FuzzyShiftOrLK([]byte(pat), text, 1, func(int, int) {})
+		}
+	}
+}
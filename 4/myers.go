@@ -0,0 +1,132 @@
+package matching
+
+import "iter"
+
+// makePeq zwraca maski znaków wzorca `pat` podzielone na
+// słowa 64-bitowe: bit `j%64` słowa `peq[c][j/64]` jest równy 1,
+// jeśli `pat[j] == c`
+func makePeq(pat []byte) *[256][]uint64 {
+	words := (len(pat) + 63) / 64
+	backing := make([]uint64, 256*words)
+	peq := [256][]uint64{}
+	for c := range peq {
+		peq[c] = backing[c*words : (c+1)*words]
+	}
+	for j, c := range pat {
+		peq[c][j/64] |= setNthBit(j % 64)
+	}
+	return &peq
+}
+
+// MyersSeq zwraca iterator po parach (i, d) dla wszystkich
+// indeksów `i` tekstu `text`, gdzie `d` to najmniejsza odległość
+// Levenshteina między wzorcem `pat` a wycinkiem `text[...:i+1]`.
+// Korzysta z algorytmu Myersa, który koduje różnice między
+// sąsiednimi elementami kolumny tablicy Wagnera-Fischera
+// w wektorach bitowych. Wzorce dłuższe niż 64 znaki dzieli
+// na bloki po 64 znaki
+func MyersSeq(pat, text []byte) iter.Seq2[int, int] {
+	return myersSeq(makePeq(pat), len(pat), text)
+}
+
+// myersSeq działa tak jak funkcja MyersSeq dla wzorca o długości
+// `n` i masek znaków `peq`, które zwraca dla niego funkcja makePeq
+func myersSeq(peq *[256][]uint64, n int, text []byte) iter.Seq2[int, int] {
+	if n > 64 {
+		return myersBlocksSeq(peq, n, text)
+	}
+	return func(yield func(int, int) bool) {
+		// Bity wektorów pv i mv są równe 1 tam, gdzie element
+		// kolumny jest o 1 większy lub o 1 mniejszy od elementu
+		// powyżej
+		pv, mv := ^uint64(0), uint64(0)
+		last := setNthBit(max(n, 1) - 1)
+		score := n
+		for i, c := range text {
+			if n > 0 {
+				eq := peq[c][0]
+				xv := eq | mv
+				xh := (((eq & pv) + pv) ^ pv) | eq
+				ph := mv | ^(xh | pv)
+				mh := pv & xh
+				if ph&last != 0 {
+					score++
+				} else if mh&last != 0 {
+					score--
+				}
+				// Górny wiersz tablicy to same zera, bo wystąpienie
+				// wzorca może zaczynać się w dowolnym miejscu tekstu
+				ph <<= 1
+				mh <<= 1
+				pv = mh | ^(xv | ph)
+				mv = ph & xv
+			}
+			if !yield(i, score) {
+				return
+			}
+		}
+	}
+}
+
+// myersBlocksSeq działa tak samo, jak funkcja myersSeq,
+// ale dla wzorców dowolnej długości
+func myersBlocksSeq(peq *[256][]uint64, n int, text []byte) iter.Seq2[int, int] {
+	return func(yield func(int, int) bool) {
+		words := len(peq[0])
+		pv := make([]uint64, words)
+		mv := make([]uint64, words)
+		for b := range pv {
+			pv[b] = ^uint64(0)
+		}
+		last := setNthBit((n - 1) % 64)
+		score := n
+		for i, c := range text {
+			// hin to różnica między elementami tablicy w wierszu
+			// nad bieżącym blokiem: -1, 0 lub +1
+			hin := 0
+			for b := range words {
+				eq := peq[c][b]
+				xv := eq | mv[b]
+				if hin < 0 {
+					eq |= 1
+				}
+				xh := (((eq & pv[b]) + pv[b]) ^ pv[b]) | eq
+				ph := mv[b] | ^(xh | pv[b])
+				mh := pv[b] & xh
+				high := setNthBit(63)
+				if b == words-1 {
+					high = last
+				}
+				hout := 0
+				if ph&high != 0 {
+					hout = 1
+				} else if mh&high != 0 {
+					hout = -1
+				}
+				ph <<= 1
+				mh <<= 1
+				if hin < 0 {
+					mh |= 1
+				} else if hin > 0 {
+					ph |= 1
+				}
+				pv[b] = mh | ^(xv | ph)
+				mv[b] = ph & xv
+				hin = hout
+			}
+			score += hin
+			if !yield(i, score) {
+				return
+			}
+		}
+	}
+}
+
+// Myers wywołuje funkcję `output(i, d)` dla wszystkich indeksów `i`
+// tekstu `text`, gdzie `d` to najmniejsza odległość Levenshteina
+// między wzorcem `pat` a wycinkiem `text[...:i+1]`
+func Myers(pat, text []byte, output func(int, int)) {
+	for i, d := range MyersSeq(pat, text) {
+		output(i, d)
+	}
+}
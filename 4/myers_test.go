@@ -0,0 +1,166 @@
+package matching
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+// approximateDistances zwraca wycinek, którego `i`-ty element to
+// najmniejsza odległość Levenshteina między wzorcem `pat`
+// a wycinkiem `text[...:i+1]`
+func approximateDistances(pat, text []byte) []int {
+	col := make([]int, len(pat)+1)
+	for j := range col {
+		col[j] = j
+	}
+	r := []int{}
+	for _, c := range text {
+		diag := col[0]
+		for j := range pat {
+			cost := 1
+			if pat[j] == c {
+				cost = 0
+			}
+			diag, col[j+1] = col[j+1], min(col[j+1]+1, col[j]+1, diag+cost)
+		}
+		r = append(r, col[len(pat)])
+	}
+	return r
+}
+
+func TestMyers(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for _, n := range []int{0, 1, 5, 63, 64, 65, 127, 128, 129, 200} {
+		for range 20 {
+			pat := make([]byte, n)
+			text := make([]byte, r.Intn(3*n+10))
+			for i := range pat {
+				pat[i] = "ab"[r.Intn(2)]
+			}
+			for i := range text {
+				text[i] = "abc"[r.Intn(3)]
+			}
+			got := []int{}
+			Myers(pat, text, func(i, d int) {
+				if i != len(got) {
+					t.Fatalf("Myers(%q, %q) zwraca indeks %d want %d",
+						pat, text, i, len(got))
+				}
+				got = append(got, d)
+			})
+			if want := approximateDistances(pat, text); !slices.Equal(got, want) {
+				t.Errorf("Myers(%q, %q) == %v want %v", pat, text, got, want)
+			}
+		}
+	}
+}
+
+func TestMyersAlphabets(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for _, alphabet := range [][]string{fullByteAlphabet, polishAlphabet} {
+		// Wzorce do około 200 bajtów
+		for _, n := range []int{1, 5, 32, 63, 64, 65, 100} {
+			for range 10 {
+				pat, text := plantedWorkload(r, alphabet, n, 5)
+				got := slices.Collect(func(yield func(int) bool) {
+					for _, d := range MyersSeq(pat, text) {
+						if !yield(d) {
+							return
+						}
+					}
+				})
+				want := approximateDistances(pat, text)
+				if !slices.Equal(got, want) {
+					t.Errorf("MyersSeq(%q, %q) == %v want %v",
+						pat, text, got, want)
+				}
+			}
+		}
+	}
+}
+
+func TestMyersShiftOr(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+	for range 100 {
+		pat := make([]byte, 1+r.Intn(64))
+		text := make([]byte, r.Intn(200))
+		for i := range pat {
+			pat[i] = "ab"[r.Intn(2)]
+		}
+		for i := range text {
+			text[i] = "ab"[r.Intn(2)]
+		}
+		for k := 0; k <= 4; k++ {
+			got := []hit{}
+			for i, d := range MyersSeq(pat, text) {
+				if d <= k {
+					got = append(got, hit{i, d})
+				}
+			}
+			want := []hit{}
+			FuzzyShiftOrLK(pat, text, k, func(i, d int) {
+				want = append(want, hit{i, d})
+			})
+			if !slices.Equal(got, want) {
+				t.Errorf("MyersSeq(%q, %q) dla k = %d == %v want %v",
+					pat, text, k, got, want)
+			}
+		}
+	}
+}
+
+// benchmarkText zwraca losowy tekst złożony z małych liter
+func benchmarkText() []byte {
+	r := rand.New(rand.NewSource(1))
+	text := make([]byte, 1<<20)
+	for i := range text {
+		text[i] = byte('a' + r.Intn(26))
+	}
+	return text
+}
+
+func BenchmarkFuzzyShiftOrL(b *testing.B) {
+	text := benchmarkText()
+	pat := []byte("domek")
+	b.SetBytes(int64(len(text)))
+	for b.Loop() {
+		FuzzyShiftOrL(pat, text, func(int) {})
+	}
+}
+
+func BenchmarkMyers(b *testing.B) {
+	text := benchmarkText()
+	pat := []byte("domek")
+	b.SetBytes(int64(len(text)))
+	for b.Loop() {
+		Myers(pat, text, func(int, int) {})
+	}
+}
+
+func BenchmarkFuzzyShiftOrLK(b *testing.B) {
+	text := benchmarkText()
+	pat := text[1000:1060]
+	b.SetBytes(int64(len(text)))
+	for b.Loop() {
+		FuzzyShiftOrLK(pat, text, 10, func(int, int) {})
+	}
+}
+
+func BenchmarkMyers60(b *testing.B) {
+	text := benchmarkText()
+	pat := text[1000:1060]
+	b.SetBytes(int64(len(text)))
+	for b.Loop() {
+		Myers(pat, text, func(int, int) {})
+	}
+}
+
+func BenchmarkMyers200(b *testing.B) {
+	text := benchmarkText()
+	pat := text[1000:1200]
+	b.SetBytes(int64(len(text)))
+	for b.Loop() {
+		Myers(pat, text, func(int, int) {})
+	}
+}
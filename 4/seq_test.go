@@ -0,0 +1,44 @@
+package matching
+
+import (
+	"iter"
+	"slices"
+	"testing"
+)
+
+func TestSeq(t *testing.T) {
+	pat := []byte("domek")
+	text := []byte("dom domek domku domek dymek")
+	data := []struct {
+		name string
+		seq  func(pat, text []byte) iter.Seq[int]
+		find func(pat, text []byte, output func(int))
+	}{
+		{"BoyerMooreSeq", BoyerMooreSeq, BoyerMoore},
+		{"FuzzyShiftOrHSeq", FuzzyShiftOrHSeq, FuzzyShiftOrH},
+		{"FuzzyShiftOrLSeq", FuzzyShiftOrLSeq, FuzzyShiftOrL},
+	}
+	for _, d := range data {
+		want := []int{}
+		d.find(pat, text, func(i int) { want = append(want, i) })
+		if got := slices.Collect(d.seq(pat, text)); !slices.Equal(got, want) {
+			t.Errorf("%s(%q, %q) == %v want %v", d.name, pat, text, got, want)
+		}
+		if len(want) < 2 {
+			t.Fatalf("%s(%q, %q) == %v: za mało wystąpień", d.name, pat,
+				text, want)
+		}
+		// Przerwanie pętli po 2 elementach
+		got := []int{}
+		for i := range d.seq(pat, text) {
+			got = append(got, i)
+			if len(got) == 2 {
+				break
+			}
+		}
+		if !slices.Equal(got, want[:2]) {
+			t.Errorf("first 2 elements of %s(%q, %q) == %v want %v",
+				d.name, pat, text, got, want[:2])
+		}
+	}
+}
@@ -0,0 +1,230 @@
+// Program atgrep wyszukuje wzorzec w plikach jednym z algorytmów
+// z pakietu github.com/MarcinCiura/AT-lab/3
+//
+// Użycie:
+//
+//	atgrep [opcje] wzorzec [plik...]
+//
+// Bez nazw plików atgrep czyta standardowe wejście
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	matching "github.com/MarcinCiura/AT-lab/3"
+)
+
+// Sekwencje ANSI, które wyróżniają wystąpienia wzorca w wierszach
+const (
+	highlightStart = "\x1b[1;31m"
+	highlightEnd   = "\x1b[0m"
+)
+
+// Opcje programu atgrep
+type Options struct {
+	// Wypisuj indeksy bajtów zamiast wierszy
+	Offsets bool
+	// Wypisuj tylko liczby wystąpień
+	Count bool
+	// Wyróżniaj wystąpienia wzorca w wierszach
+	Highlight bool
+	// Poprzedzaj wiersze wyniku nazwą pliku
+	Filename bool
+}
+
+// Input to nazwa i zawartość jednego przeszukiwanego pliku
+type Input struct {
+	Name string
+	Text []byte
+}
+
+// Grep wypisuje do `w` wystąpienia wzorca o długości `n`, które
+// znajduje w tekście `in.Text` obiekt `m`, w formacie określonym
+// przez `opts`. Zwraca liczbę wystąpień
+func Grep(w io.Writer, m matching.Matcher, n int, in Input, opts Options) int {
+	prefix := ""
+	if opts.Filename {
+		prefix = in.Name + ":"
+	}
+	matches := []int{}
+	m.FindAll(in.Text, func(i int) { matches = append(matches, i) })
+	count := len(matches)
+	switch {
+	case opts.Count:
+		fmt.Fprintf(w, "%s%d\n", prefix, count)
+	case opts.Offsets:
+		for _, i := range matches {
+			fmt.Fprintf(w, "%s%d\n", prefix, i)
+		}
+	default:
+		for len(matches) > 0 {
+			// Wypisz wiersz, w którym zaczyna się pierwsze
+			// wystąpienie, i wszystkie wystąpienia w tym wierszu
+			start := bytes.LastIndexByte(in.Text[:matches[0]], '\n') + 1
+			if start == len(in.Text) && start > 0 {
+				// Puste wystąpienie za ostatnim znakiem '\n'
+				// nie należy do żadnego wiersza
+				break
+			}
+			// Wiersz kończy się najwcześniej na ostatnim bajcie
+			// wystąpienia, który może być znakiem '\n'
+			last := matches[0] + max(n-1, 0)
+			end := bytes.IndexByte(in.Text[last:], '\n')
+			if end < 0 {
+				end = len(in.Text)
+			} else {
+				end += last
+			}
+			// Wystąpienie, które zaczyna się od znaku '\n' na końcu
+			// wiersza, należy do tego wiersza. Dzięki temu k > 0
+			// także dla pustego wzorca
+			k := 0
+			for k < len(matches) && matches[k] <= end {
+				k++
+			}
+			line := in.Text[start:end]
+			if opts.Highlight {
+				line = HighlightLine(line, matches[:k], start, n)
+			}
+			fmt.Fprintf(w, "%s%s\n", prefix, line)
+			matches = matches[k:]
+		}
+	}
+	return count
+}
+
+// HighlightLine zwraca wiersz `line`, który zaczyna się w tekście na
+// pozycji `start`, z wyróżnionymi wystąpieniami wzorca o długości `n`
+// na pozycjach `matches`. Nakładające się wystąpienia łączy
+func HighlightLine(line []byte, matches []int, start, n int) []byte {
+	var b bytes.Buffer
+	pos := 0
+	for k := 0; k < len(matches); {
+		from := matches[k] - start
+		to := from + n
+		k++
+		for k < len(matches) && matches[k]-start <= to {
+			to = max(to, matches[k]-start+n)
+			k++
+		}
+		to = min(to, len(line))
+		if from >= to {
+			// Puste wystąpienie albo sam znak '\n' za wierszem
+			continue
+		}
+		b.Write(line[pos:from])
+		b.WriteString(highlightStart)
+		b.Write(line[from:to])
+		b.WriteString(highlightEnd)
+		pos = to
+	}
+	b.Write(line[pos:])
+	return b.Bytes()
+}
+
+// Bench wypisuje do `w` czas wyszukiwania wzorca `pat` w tekstach
+// `inputs` wszystkimi algorytmami zapisanymi w zmiennej
+// `matching.Matchers`
+func Bench(w io.Writer, pat []byte, inputs []Input) {
+	size := 0
+	for _, in := range inputs {
+		size += len(in.Text)
+	}
+	fmt.Fprintf(w, "%-14s %10s %14s %10s\n", "algorytm", "wystąpienia",
+		"czas", "MB/s")
+	for _, name := range slices.Sorted(maps.Keys(matching.Matchers)) {
+		count := 0
+		t := time.Now()
+		m := matching.Matchers[name](pat)
+		for _, in := range inputs {
+			m.FindAll(in.Text, func(int) { count++ })
+		}
+		d := time.Since(t)
+		fmt.Fprintf(w, "%-14s %10d %14v %10.1f\n", name, count, d,
+			float64(size)/d.Seconds()/1e6)
+	}
+}
+
+// readInputs zwraca zawartość plików `names` lub standardowego
+// wejścia, jeśli `names` jest pusty
+func readInputs(names []string) []Input {
+	if len(names) == 0 {
+		text, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			log.Fatal(err)
+		}
+		return []Input{{"(standardowe wejście)", text}}
+	}
+	inputs := []Input{}
+	for _, name := range names {
+		text, err := os.ReadFile(name)
+		if err != nil {
+			log.Fatal(err)
+		}
+		inputs = append(inputs, Input{name, text})
+	}
+	return inputs
+}
+
+// isTerminal zwraca `true`, jeśli plik `f` to terminal
+func isTerminal(f *os.File) bool {
+	fi, err := f.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("atgrep: ")
+	names := slices.Sorted(maps.Keys(matching.Matchers))
+	algo := flag.String("algo", "bm",
+		"algorytm wyszukiwania: "+strings.Join(names, ", "))
+	offsets := flag.Bool("b", false, "wypisuj indeksy bajtów wystąpień")
+	count := flag.Bool("c", false, "wypisuj tylko liczby wystąpień")
+	color := flag.String("color", "auto",
+		"wyróżniaj wystąpienia: auto, always lub never")
+	bench := flag.Bool("bench", false,
+		"zmierz czas wyszukiwania wszystkimi algorytmami")
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(),
+			"Użycie: atgrep [opcje] wzorzec [plik...]")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() < 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	compile, ok := matching.Matchers[*algo]
+	if !ok {
+		log.Fatalf("Nie znam algorytmu %s", *algo)
+	}
+	pat := []byte(flag.Arg(0))
+	inputs := readInputs(flag.Args()[1:])
+	if *bench {
+		Bench(os.Stdout, pat, inputs)
+		return
+	}
+	opts := Options{
+		Offsets:   *offsets,
+		Count:     *count,
+		Highlight: *color == "always" || *color == "auto" && isTerminal(os.Stdout),
+		Filename:  len(inputs) > 1,
+	}
+	m := compile(pat)
+	total := 0
+	for _, in := range inputs {
+		total += Grep(os.Stdout, m, len(pat), in, opts)
+	}
+	if total == 0 {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	matching "github.com/MarcinCiura/AT-lab/3"
+)
+
+func TestGrep(t *testing.T) {
+	text := "ala ma kota\nkot ma ale\npies\nkotkot\n"
+	data := []struct {
+		pat   string
+		opts  Options
+		count int
+		want  string
+	}{
+		{"kot", Options{}, 4, "ala ma kota\nkot ma ale\nkotkot\n"},
+		{"kot", Options{Count: true}, 4, "4\n"},
+		{"kot", Options{Offsets: true}, 4, "7\n12\n28\n31\n"},
+		{"ma", Options{Filename: true}, 2, "f:ala ma kota\nf:kot ma ale\n"},
+		{"pies", Options{Highlight: true}, 1, "\x1b[1;31mpies\x1b[0m\n"},
+		{"kotkot", Options{Highlight: true}, 1,
+			"\x1b[1;31mkotkot\x1b[0m\n"},
+		{"lis", Options{}, 0, ""},
+		{"lis", Options{Count: true}, 0, "0\n"},
+		// Wystąpienia na granicach wierszy
+		{"\n", Options{}, 4, "ala ma kota\nkot ma ale\npies\nkotkot\n"},
+		{"a\nk", Options{Highlight: true}, 1,
+			"ala ma kot\x1b[1;31ma\nk\x1b[0mot ma ale\n"},
+		{"kot\n", Options{Highlight: true}, 1,
+			"kot\x1b[1;31mkot\x1b[0m\n"},
+		// Pusty wzorzec występuje w każdym wierszu
+		{"", Options{}, 36, "ala ma kota\nkot ma ale\npies\nkotkot\n"},
+		{"", Options{Highlight: true}, 36,
+			"ala ma kota\nkot ma ale\npies\nkotkot\n"},
+	}
+	for _, d := range data {
+		var b bytes.Buffer
+		m := matching.Matchers["bm"]([]byte(d.pat))
+		count := Grep(&b, m, len(d.pat), Input{"f", []byte(text)}, d.opts)
+		if count != d.count || b.String() != d.want {
+			t.Errorf("Grep(%q, %#v) == %d, %q want %d, %q",
+				d.pat, d.opts, count, b.String(), d.count, d.want)
+		}
+	}
+}
+
+func TestHighlightLine(t *testing.T) {
+	data := []struct {
+		line    string
+		matches []int
+		start   int
+		n       int
+		want    string
+	}{
+		{"abc", []int{10}, 10, 1, "[a]bc"},
+		{"abcabc", []int{0, 3}, 0, 3, "[abcabc]"},
+		{"aaaa", []int{5, 6, 7}, 5, 2, "[aaaa]"},
+		{"xaax", []int{1, 2}, 0, 1, "x[aa]x"},
+		{"ab", []int{1}, 0, 3, "a[b]"},
+		{"ab", []int{0, 1, 2}, 0, 0, "ab"},
+	}
+	for _, d := range data {
+		got := string(HighlightLine([]byte(d.line), d.matches, d.start, d.n))
+		got = strings.NewReplacer(highlightStart, "[",
+			highlightEnd, "]").Replace(got)
+		if got != d.want {
+			t.Errorf("HighlightLine(%q, %v, %d, %d) == %q want %q",
+				d.line, d.matches, d.start, d.n, got, d.want)
+		}
+	}
+}
+
+func TestBench(t *testing.T) {
+	var b bytes.Buffer
+	Bench(&b, []byte("ab"), []Input{{"f", []byte("abab")}, {"g", []byte("ab")}})
+	lines := strings.Split(strings.TrimSpace(b.String()), "\n")
+	if len(lines) != len(matching.Matchers)+1 {
+		t.Fatalf("Bench wypisał %d wierszy want %d",
+			len(lines), len(matching.Matchers)+1)
+	}
+	for _, line := range lines[1:] {
+		if f := strings.Fields(line); f[1] != "3" {
+			t.Errorf("Bench: %q want 3 wystąpienia", line)
+		}
+	}
+}